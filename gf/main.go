@@ -42,6 +42,8 @@ func main() {
 		cmd.Gen,
 		cmd.Tpl,
 		cmd.Init,
+		cmd.Migrate,
+		cmd.Db,
 		cmd.Pack,
 		cmd.Build,
 		cmd.Docker,
@@ -0,0 +1,497 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/encoding/gyaml"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/gogf/gf/v2/util/gtag"
+)
+
+var (
+	Db = cDb{}
+)
+
+type cDb struct {
+	g.Meta `name:"db" brief:"{cDbBrief}" dc:"{cDbDc}" ad:"{cDbAd}"`
+}
+
+const (
+	cDbConfig      = `gfcli.db`
+	cDbDefaultPath = `manifest/fixture`
+	cDbBrief       = `export and import database fixture data`
+	cDbDc          = `
+The "db" command is used for exporting table rows into fixture files and loading them back into database,
+which makes reproducible data for integration tests and local environments.
+Each fixture file contains rows of one table, and it is named as "{TABLE}.{FORMAT}".
+Please use "gf db dump -h" for specified command help.
+`
+	cDbAd = `
+CONFIGURATION SUPPORT
+    Options are also supported by configuration file.
+    The database configuration is the same as "gf gen dao", which uses "link" or "group" of the ORM configuration.
+    The configuration node name is "gfcli.db", in which the "where" can also be a map for conditions of each table,
+    for example(config.yaml):
+    gfcli:
+      db:
+        link:     "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+        path:     "manifest/fixture"
+        tables:   "user,user_detail,order_*"
+        tablesEx: "order_log"
+        format:   "yaml"
+        limit:    100
+        where:
+          user:  "status=1"
+          order: "created_at>'2022-01-01'"
+`
+	cDbDumpBrief = `export rows of selected tables into fixture files`
+	cDbDumpEg    = `
+gf db dump
+gf db dump -t user,user_detail -f json
+gf db dump -t "order_*" -w "id<1000" -n 100 -f csv
+gf db dump -l "mysql:root:12345678@tcp(127.0.0.1:3306)/test" -p ./testdata/fixture
+`
+	cDbSeedBrief = `load fixture files into database in dependency order inside a transaction`
+	cDbSeedEg    = `
+gf db seed
+gf db seed -t user,user_detail
+gf db seed --truncate
+gf db seed -l "mysql:root:12345678@tcp(127.0.0.1:3306)/test" -p ./testdata/fixture
+`
+	cDbBriefPath     = `directory path for fixture files, default is "manifest/fixture"`
+	cDbBriefLink     = `database configuration, the same as the ORM configuration of GoFrame`
+	cDbBriefTables   = `only the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cDbBriefTablesEx = `excluding the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cDbBriefWhere    = `condition for filtering the exported rows of each table, like: "status=1"`
+	cDbBriefLimit    = `max count of exported rows for each table, no limit if it's 0`
+	cDbBriefFormat   = `format of the fixture files, which can be: yaml, json, csv. default is "yaml"`
+	cDbBriefTruncate = `delete all rows of the seeded tables before loading fixtures`
+	cDbBriefGroup    = `
+specifying the configuration group name of database,
+it's not necessary and the default value is "default"
+`
+)
+
+const (
+	// fixtureCsvNull is the representation of NULL value in csv fixture file.
+	fixtureCsvNull = `\N`
+	// fixtureBase64Key is the key of the object representing binary value in fixture file,
+	// like: {"$base64": "AAEC"}, which is the json string of the object in csv fixture file.
+	fixtureBase64Key = `$base64`
+	// fixtureBatchSize is the batch count for inserting fixture rows.
+	fixtureBatchSize = 100
+)
+
+func init() {
+	gtag.Sets(g.MapStrStr{
+		`cDbBrief`:         cDbBrief,
+		`cDbDc`:            cDbDc,
+		`cDbAd`:            cDbAd,
+		`cDbDumpBrief`:     cDbDumpBrief,
+		`cDbDumpEg`:        cDbDumpEg,
+		`cDbSeedBrief`:     cDbSeedBrief,
+		`cDbSeedEg`:        cDbSeedEg,
+		`cDbBriefPath`:     cDbBriefPath,
+		`cDbBriefLink`:     cDbBriefLink,
+		`cDbBriefTables`:   cDbBriefTables,
+		`cDbBriefTablesEx`: cDbBriefTablesEx,
+		`cDbBriefWhere`:    cDbBriefWhere,
+		`cDbBriefLimit`:    cDbBriefLimit,
+		`cDbBriefFormat`:   cDbBriefFormat,
+		`cDbBriefTruncate`: cDbBriefTruncate,
+		`cDbBriefGroup`:    cDbBriefGroup,
+	})
+}
+
+type (
+	cDbDumpInput struct {
+		g.Meta   `name:"dump" brief:"{cDbDumpBrief}" eg:"{cDbDumpEg}"`
+		Path     string `name:"path"     short:"p" brief:"{cDbBriefPath}"`
+		Link     string `name:"link"     short:"l" brief:"{cDbBriefLink}"`
+		Group    string `name:"group"    short:"g" brief:"{cDbBriefGroup}"`
+		Tables   string `name:"tables"   short:"t" brief:"{cDbBriefTables}"`
+		TablesEx string `name:"tablesEx" short:"e" brief:"{cDbBriefTablesEx}"`
+		Where    string `name:"where"    short:"w" brief:"{cDbBriefWhere}"`
+		Limit    int    `name:"limit"    short:"n" brief:"{cDbBriefLimit}"`
+		Format   string `name:"format"   short:"f" brief:"{cDbBriefFormat}"`
+	}
+	cDbDumpOutput struct{}
+
+	cDbSeedInput struct {
+		g.Meta   `name:"seed" brief:"{cDbSeedBrief}" eg:"{cDbSeedEg}"`
+		Path     string `name:"path"     short:"p" brief:"{cDbBriefPath}"`
+		Link     string `name:"link"     short:"l" brief:"{cDbBriefLink}"`
+		Group    string `name:"group"    short:"g" brief:"{cDbBriefGroup}"`
+		Tables   string `name:"tables"   short:"t" brief:"{cDbBriefTables}"`
+		TablesEx string `name:"tablesEx" short:"e" brief:"{cDbBriefTablesEx}"`
+		Truncate bool   `name:"truncate" short:"c" brief:"{cDbBriefTruncate}" orphan:"true"`
+	}
+	cDbSeedOutput struct{}
+
+	// cDbConfigInput is the configuration of "gfcli.db".
+	cDbConfigInput struct {
+		Path     string
+		Link     string
+		Group    string
+		Tables   string
+		TablesEx string
+		Limit    int
+		Format   string
+	}
+)
+
+func (c cDb) Dump(ctx context.Context, in cDbDumpInput) (out *cDbDumpOutput, err error) {
+	var (
+		config     = loadDbConfig(ctx)
+		whereMap   = make(map[string]string)
+		tableNames []string
+	)
+	in.Path = firstNonEmpty(in.Path, config.Path, cDbDefaultPath)
+	in.Link = firstNonEmpty(in.Link, config.Link)
+	in.Group = firstNonEmpty(in.Group, config.Group, gdb.DefaultGroupName)
+	in.Tables = firstNonEmpty(in.Tables, config.Tables)
+	in.TablesEx = firstNonEmpty(in.TablesEx, config.TablesEx)
+	in.Format = gstr.ToLower(firstNonEmpty(in.Format, config.Format, "yaml"))
+	if in.Limit == 0 {
+		in.Limit = config.Limit
+	}
+	// The "where" configuration can be either a string or a map.
+	if in.Where == "" && g.Cfg().Available(ctx) {
+		if v := g.Cfg().MustGet(ctx, cDbConfig+".where"); v.IsMap() {
+			whereMap = v.MapStrStr()
+		} else {
+			in.Where = v.String()
+		}
+	}
+	switch in.Format {
+	case "yaml", "yml", "json", "csv":
+	default:
+		mlog.Fatalf(`unsupported fixture format "%s"`, in.Format)
+	}
+
+	db := getDatabase(in.Link, in.Group)
+	if db == nil {
+		mlog.Fatal("database initialization failed")
+	}
+	if tableNames, err = db.Tables(ctx); err != nil {
+		mlog.Fatalf("fetching tables failed: \n %v", err)
+	}
+	tableNames = filterTableNames(tableNames, in.Tables, in.TablesEx)
+	if len(tableNames) == 0 {
+		mlog.Fatal("no table matches the given table patterns")
+	}
+	for _, tableName := range tableNames {
+		var (
+			model = db.Model(tableName).Safe().Ctx(ctx)
+			where = in.Where
+		)
+		if v, ok := whereMap[tableName]; ok {
+			where = v
+		}
+		if where != "" {
+			model = model.Where(where)
+		}
+		if in.Limit > 0 {
+			model = model.Limit(in.Limit)
+		}
+		if primary := loadTablePrimaryKeys(ctx, db, tableName); len(primary) > 0 {
+			model = model.Order(gstr.Join(primary, ","))
+		}
+		result, err := model.All()
+		if err != nil {
+			mlog.Fatalf(`fetching rows failed for table "%s": %+v`, tableName, err)
+		}
+		fieldMap, err := db.TableFields(ctx, tableName)
+		if err != nil {
+			mlog.Fatalf("fetching tables fields failed for table '%s':\n%v", tableName, err)
+		}
+		var (
+			path    = gfile.Join(in.Path, tableName+"."+in.Format)
+			content []byte
+		)
+		content, err = encodeFixture(sortFieldKeyForDao(fieldMap), result, in.Format)
+		if err != nil {
+			mlog.Fatalf(`encoding fixture failed for table "%s": %+v`, tableName, err)
+		}
+		if err = gfile.PutBytes(path, content); err != nil {
+			mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+		}
+		mlog.Printf("generated: %s (%d rows)", path, len(result))
+	}
+	mlog.Print("done!")
+	return
+}
+
+func (c cDb) Seed(ctx context.Context, in cDbSeedInput) (out *cDbSeedOutput, err error) {
+	config := loadDbConfig(ctx)
+	in.Path = firstNonEmpty(in.Path, config.Path, cDbDefaultPath)
+	in.Link = firstNonEmpty(in.Link, config.Link)
+	in.Group = firstNonEmpty(in.Group, config.Group, gdb.DefaultGroupName)
+	in.Tables = firstNonEmpty(in.Tables, config.Tables)
+	in.TablesEx = firstNonEmpty(in.TablesEx, config.TablesEx)
+	if !gfile.Exists(in.Path) {
+		mlog.Fatalf(`fixture path "%s" does not exist`, in.Path)
+	}
+	files, err := gfile.ScanDirFile(in.Path, "*.yaml,*.yml,*.json,*.csv", false)
+	if err != nil {
+		mlog.Fatal(err)
+	}
+	var (
+		fileMap    = make(map[string]string)
+		tableNames = make([]string, 0)
+	)
+	for _, file := range files {
+		tableName := gfile.Name(file)
+		if existing, ok := fileMap[tableName]; ok {
+			mlog.Fatalf(`duplicated fixture files "%s" and "%s" for table "%s"`, existing, file, tableName)
+		}
+		fileMap[tableName] = file
+		tableNames = append(tableNames, tableName)
+	}
+	tableNames = filterTableNames(tableNames, in.Tables, in.TablesEx)
+	if len(tableNames) == 0 {
+		mlog.Fatalf(`no fixture file found in "%s"`, in.Path)
+	}
+
+	db := getDatabase(in.Link, in.Group)
+	if db == nil {
+		mlog.Fatal("database initialization failed")
+	}
+	// Sort the tables in dependency order by their foreign keys.
+	dependencies := make(map[string][]string)
+	for _, tableName := range tableNames {
+		foreignKeys, err := loadTableForeignKeys(ctx, db, tableName)
+		if err != nil {
+			mlog.Fatalf(`fetching foreign keys failed for table "%s": %+v`, tableName, err)
+		}
+		for _, foreignKey := range foreignKeys {
+			dependencies[tableName] = append(dependencies[tableName], foreignKey.ReferencedTable)
+		}
+	}
+	tableNames = sortTableNamesByDependency(tableNames, dependencies)
+
+	// Load all fixtures before database operations.
+	fixtures := make(map[string]gdb.List)
+	for _, tableName := range tableNames {
+		if fixtures[tableName], err = decodeFixture(fileMap[tableName]); err != nil {
+			mlog.Fatal(err)
+		}
+	}
+	err = db.Transaction(ctx, func(ctx context.Context, tx *gdb.TX) error {
+		if in.Truncate {
+			// Reverse dependency order for deleting.
+			for i := len(tableNames) - 1; i >= 0; i-- {
+				if _, err := tx.Model(tableNames[i]).Ctx(ctx).Where("1=1").Delete(); err != nil {
+					return gerror.Wrapf(err, `deleting rows of table "%s" failed`, tableNames[i])
+				}
+				mlog.Printf(`truncated: %s`, tableNames[i])
+			}
+		}
+		for _, tableName := range tableNames {
+			list := fixtures[tableName]
+			if len(list) > 0 {
+				if _, err := tx.Model(tableName).Ctx(ctx).Data(list).Batch(fixtureBatchSize).Insert(); err != nil {
+					return gerror.Wrapf(err, `inserting fixture rows of table "%s" failed`, tableName)
+				}
+			}
+			mlog.Printf(`seeded: %s (%d rows)`, tableName, len(list))
+		}
+		return nil
+	})
+	if err != nil {
+		mlog.Fatalf(`seeding failed and all changes are rolled back: %+v`, err)
+	}
+	mlog.Print("done!")
+	return
+}
+
+// loadDbConfig loads and returns the configuration of "db" command.
+func loadDbConfig(ctx context.Context) (config cDbConfigInput) {
+	if g.Cfg().Available(ctx) {
+		if err := g.Cfg().MustGet(ctx, cDbConfig).Scan(&config); err != nil {
+			mlog.Fatalf(`invalid configuration of "%s": %+v`, cDbConfig, err)
+		}
+	}
+	return
+}
+
+// loadTablePrimaryKeys retrieves and returns the primary key column names of given table.
+func loadTablePrimaryKeys(ctx context.Context, db gdb.DB, tableName string) []string {
+	fieldMap, err := db.TableFields(ctx, tableName)
+	if err != nil {
+		return nil
+	}
+	primary := make([]string, 0)
+	for _, name := range sortFieldKeyForDao(fieldMap) {
+		if gstr.ContainsI(fieldMap[name].Key, "pri") {
+			primary = append(primary, name)
+		}
+	}
+	return primary
+}
+
+// encodeFixture encodes the rows to fixture content in given format.
+// The `columns` specifies the column order for csv format.
+func encodeFixture(columns []string, result gdb.Result, format string) ([]byte, error) {
+	list := make([]map[string]interface{}, len(result))
+	for i, record := range result {
+		item := make(map[string]interface{}, len(record))
+		for k, v := range record {
+			item[k] = fixtureValue(v.Val())
+		}
+		list[i] = item
+	}
+	switch format {
+	case "json":
+		return json.MarshalIndent(list, "", "\t")
+
+	case "csv":
+		var (
+			buffer = bytes.NewBuffer(nil)
+			writer = csv.NewWriter(buffer)
+		)
+		if err := writer.Write(columns); err != nil {
+			return nil, err
+		}
+		for _, item := range list {
+			row := make([]string, len(columns))
+			for i, column := range columns {
+				if item[column] == nil {
+					row[i] = fixtureCsvNull
+				} else {
+					row[i] = gconv.String(item[column])
+				}
+			}
+			if err := writer.Write(row); err != nil {
+				return nil, err
+			}
+		}
+		writer.Flush()
+		return buffer.Bytes(), writer.Error()
+	}
+	return gjson.New(list).ToYaml()
+}
+
+// decodeFixture decodes and returns the rows from fixture file.
+func decodeFixture(path string) (gdb.List, error) {
+	var (
+		list    = make(gdb.List, 0)
+		content = gfile.GetBytes(path)
+	)
+	switch {
+	case gfile.ExtName(path) == "csv":
+		records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+		if err != nil {
+			return nil, gerror.Wrapf(err, `invalid csv fixture file "%s"`, path)
+		}
+		if len(records) == 0 {
+			return list, nil
+		}
+		columns := records[0]
+		for _, record := range records[1:] {
+			item := make(gdb.Map, len(columns))
+			for i, column := range columns {
+				if i >= len(record) || record[i] == fixtureCsvNull {
+					item[column] = nil
+				} else {
+					item[column] = record[i]
+				}
+			}
+			list = append(list, item)
+		}
+
+	case len(bytes.TrimSpace(content)) == 0:
+		return list, nil
+
+	case gfile.ExtName(path) == "yaml" || gfile.ExtName(path) == "yml":
+		// The yaml content is decoded directly, as gjson supports only yaml object at top level.
+		items := make([]map[string]interface{}, 0)
+		if err := gyaml.DecodeTo(content, &items); err != nil {
+			return nil, gerror.Wrapf(err, `invalid fixture file "%s"`, path)
+		}
+		for _, item := range items {
+			list = append(list, item)
+		}
+
+	default:
+		j, err := gjson.LoadContent(content)
+		if err != nil {
+			return nil, gerror.Wrapf(err, `invalid fixture file "%s"`, path)
+		}
+		for _, item := range j.Array() {
+			list = append(list, gconv.Map(item))
+		}
+	}
+	// Binary values decoding.
+	for _, item := range list {
+		for column, value := range item {
+			decoded, err := decodeFixtureValue(value)
+			if err != nil {
+				return nil, gerror.Wrapf(err, `invalid binary value of column "%s" in fixture file "%s"`, column, path)
+			}
+			item[column] = decoded
+		}
+	}
+	return list, nil
+}
+
+// fixtureValue converts the database value to the value that can be encoded in fixture file.
+func fixtureValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, string,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return v
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return map[string]interface{}{
+			fixtureBase64Key: base64.StdEncoding.EncodeToString(v),
+		}
+	}
+	return gconv.String(value)
+}
+
+// decodeFixtureValue converts the value decoded from fixture file back to database value,
+// which decodes the binary value from its base64 object, see fixtureValue.
+func decodeFixtureValue(value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok && gstr.HasPrefix(s, `{"`+fixtureBase64Key+`"`) {
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &object); err == nil {
+			value = object
+		}
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok || len(object) != 1 {
+		return value, nil
+	}
+	encoded, ok := object[fixtureBase64Key]
+	if !ok {
+		return value, nil
+	}
+	return base64.StdEncoding.DecodeString(gconv.String(encoded))
+}
+
+// firstNonEmpty returns the first non-empty string of given values.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
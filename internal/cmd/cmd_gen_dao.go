@@ -8,15 +8,17 @@ import (
 
 	"github.com/gogf/gf-cli/v2/internal/consts"
 	"github.com/gogf/gf-cli/v2/utility/mlog"
-	"github.com/gogf/gf-cli/v2/utility/utils"
 	"github.com/gogf/gf/v2/container/garray"
 	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gstructs"
 	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/text/gregex"
 	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
 	"github.com/gogf/gf/v2/util/gtag"
+	"github.com/gogf/gf/v2/util/gutil"
 	"github.com/olekukonko/tablewriter"
 
 	_ "github.com/denisenkom/go-mssqldb"
@@ -55,6 +57,79 @@ CONFIGURATION SUPPORT
 		  path:   "./my-app"
 		  prefix: "primary_"
 		  tables: "user, userDetail"
+
+DATABASE LINK
+    The "${VAR}" and "${VAR:-default}" references in "link" are replaced with environment variables,
+    and the "file://PATH" or "${file://PATH}" references are replaced with the content of secret files.
+    The "link" can also be structured configuration, which is the same as the ORM configuration node, for example:
+	gfcli:
+	  gen:
+		dao:
+		- link:
+			type:    "mysql"
+			host:    "${DB_HOST:-127.0.0.1}"
+			port:    "3306"
+			user:    "root"
+			pass:    "file:///run/secrets/db_pass"
+			name:    "test"
+			charset: "utf8mb4"
+			extra:   "parseTime=true"
+    The credentials are masked in the debug logs, which are shown using "--debug" option.
+    The column types of "mssql" and "oracle" are mapped with their own type mappings, like "uniqueidentifier"
+    to string and "NUMBER(p,s)" to int/int64/float64 by its precision and scale. Note that the ORM of the
+    GoFrame version used by this tool registers only the "mysql" database driver, so the links like "mssql:..."
+    and "oracle:..." fail in connecting, and the mappings take effect only with an ORM registering their drivers.
+
+SHARDED TABLES
+    The sharded tables like "order_0" ... "order_63" can be collapsed into one logical table using "sharding" rules,
+    in which the wildcard char '*' of "pattern" matches the shard suffix, for example:
+	gfcli:
+	  gen:
+		dao:
+		- link:     "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+		  sharding:
+		  - pattern: "order_*"
+			name:    "order"
+		  - pattern: "log_*"
+			name:    "log"
+    All the shards are verified sharing the same columns, it fails if any shard diverges.
+    The generated DAO has method "ShardTable" resolving the physical table name from shard key,
+    which is "key % count" for shards "0" to "count-1", time format for shards like "2024", "202401" or "20240101",
+    or else the key itself as the shard suffix.
+    There is no physical table of the logical name, so the operations should use method "Shard(ctx, key)"
+    creating the Model on the resolved physical table, and the method "Ctx" of sharded DAO panics.
+    The "Table" method and the "orm" tag of DO return the logical name, the DO is used as data or condition
+    of the Model of "Shard", for example:
+	dao.Order.Shard(ctx, userId).Data(do.Order{UserId: userId}).Insert()
+
+MULTI-TENANT GROUP ROUTING
+    The "groupResolver" option generates hook "dao.SetGroupResolver" for resolving the database configuration group
+    of DAOs from context at call time, which is used by their "DB(ctx)", "Ctx" and "Transaction" methods,
+    falling back to the configured group if the hook is not set or returns empty string, for example:
+	dao.SetGroupResolver(func(ctx context.Context, group string) string {
+		return "tenant_" + g.RequestFromCtx(ctx).GetHeader("X-Tenant-Id")
+	})
+
+INCREMENTAL GENERATING
+    The hash of each table's metadata, generating options and templates is cached in file ".gf-gen-dao-cache.json"
+    of working directory, the tables are skipped in following generating if their hashes are not changed
+    and their generated files exist. Use "--all" option to regenerate all the tables.
+
+STORED PROCEDURES AND FUNCTIONS
+    The stored procedures and functions matching "routines" patterns are wrapped as typed methods of DAO "Routine",
+    or "{Group}Routine" for non-default group, which are called using the database of the group, for example:
+	result, err := dao.Routine.CalcOrderAmount(ctx, orderId)
+    The function returns its typed value, the procedure having OUT/INOUT parameters returns struct of the parameters,
+    and the mysql procedure without OUT/INOUT parameters returns its result set.
+    It supports mysql and pgsql currently.
+
+COLUMN COMMENT DIRECTIVES
+    The directives in column comments control the generating of the field, which are removed from generated comments:
+    @type:github.com/x/money.Amount  use custom golang type for the field, the package is imported automatically
+    @json:-                          ignore the field in json
+    @omitempty                       add "omitempty" option to json tag
+    @enum:active,disabled            add validation rule "in:active,disabled" to entity field
+    @deprecated                      mark the field deprecated
 `
 	cGenDaoBriefPath            = `directory path for generated files`
 	cGenDaoBriefLink            = `database configuration, the same as the ORM configuration of GoFrame`
@@ -71,6 +146,13 @@ CONFIGURATION SUPPORT
 	cGenDaoBriefDescriptionTag  = `add comment to description tag for each field`
 	cGenDaoBriefNoJsonTag       = `no json tag will be added for each field`
 	cGenDaoBriefNoModelComment  = `no model comment will be added for each field`
+	cGenDaoBriefMock            = `generate interface, primary key methods and in-memory fake for each DAO, which requires single primary key`
+	cGenDaoBriefSharding        = `sharding rules collapsing sharded tables into one DAO, like "order_*:order,log_*:log"`
+	cGenDaoBriefRoutines        = `generate wrappers for stored procedures and functions matching given patterns, like "sp_*,fn_*" or "*"`
+	cGenDaoBriefForce           = `overwrite the generated files even if they were modified after last generating`
+	cGenDaoBriefGroupResolver   = `generate group resolver hook resolving database group of DAOs from context at call time`
+	cGenDaoBriefAll             = `regenerate all tables even if they are not changed since last generating`
+	cGenDaoBriefTypedColumns    = `generate typed column descriptors with type-checked condition helpers for each DAO`
 	cGenDaoBriefGroup           = `
 specifying the configuration group name of database for generated ORM instance,
 it's not necessary and the default value is "default"
@@ -126,6 +208,13 @@ func init() {
 		`cGenDaoBriefDescriptionTag`:  cGenDaoBriefDescriptionTag,
 		`cGenDaoBriefNoJsonTag`:       cGenDaoBriefNoJsonTag,
 		`cGenDaoBriefNoModelComment`:  cGenDaoBriefNoModelComment,
+		`cGenDaoBriefMock`:            cGenDaoBriefMock,
+		`cGenDaoBriefTypedColumns`:    cGenDaoBriefTypedColumns,
+		`cGenDaoBriefForce`:           cGenDaoBriefForce,
+		`cGenDaoBriefAll`:             cGenDaoBriefAll,
+		`cGenDaoBriefGroupResolver`:   cGenDaoBriefGroupResolver,
+		`cGenDaoBriefSharding`:        cGenDaoBriefSharding,
+		`cGenDaoBriefRoutines`:        cGenDaoBriefRoutines,
 		`cGenDaoBriefGroup`:           cGenDaoBriefGroup,
 		`cGenDaoBriefJsonCase`:        cGenDaoBriefJsonCase,
 	})
@@ -151,14 +240,24 @@ type (
 		DescriptionTag bool   `name:"descriptionTag"  short:"d" brief:"{cGenDaoBriefDescriptionTag}"  orphan:"true"`
 		NoJsonTag      bool   `name:"noJsonTag"       short:"k" brief:"{cGenDaoBriefNoJsonTag"        orphan:"true"`
 		NoModelComment bool   `name:"noModelComment"  short:"m" brief:"{cGenDaoBriefNoModelComment}"  orphan:"true"`
+		Mock           bool   `name:"mock"            short:"a" brief:"{cGenDaoBriefMock}"            orphan:"true"`
+		TypedColumns   bool   `name:"typedColumns"    short:"u" brief:"{cGenDaoBriefTypedColumns}"    orphan:"true"`
+		Sharding       string `name:"sharding"        short:"z" brief:"{cGenDaoBriefSharding}"`
+		Routines       string `name:"routines"        short:"q" brief:"{cGenDaoBriefRoutines}"`
+		Force          bool   `name:"force"                     brief:"{cGenDaoBriefForce}"           orphan:"true"`
+		GroupResolver  bool   `name:"groupResolver"   short:"w" brief:"{cGenDaoBriefGroupResolver}"   orphan:"true"`
+		All            bool   `name:"all"                       brief:"{cGenDaoBriefAll}"             orphan:"true"`
 	}
 	cGenDaoOutput struct{}
 
 	cGenDaoInternalInput struct {
 		cGenDaoInput
-		TableName    string // TableName specifies the table name of the table.
-		NewTableName string // NewTableName specifies the prefix-stripped name of the table.
-		ModName      string // ModName specifies the module name of current golang project, which is used for import purpose.
+		TableName    string                    // TableName specifies the table name of the table.
+		NewTableName string                    // NewTableName specifies the prefix-stripped name of the table.
+		ModName      string                    // ModName specifies the module name of current golang project, which is used for import purpose.
+		DbType       string                    // DbType specifies the database type of the link, which is used for type mapping.
+		ShardTables  map[string]*daoShardTable // ShardTables maps logical table names to their sharded physical tables.
+		Shard        *daoShardTable            // Shard specifies the sharded physical tables if the table is logical table.
 	}
 )
 
@@ -179,6 +278,32 @@ func (c cGen) Dao(ctx context.Context, in cGenDaoInput) (out *cGenDaoOutput, err
 	return
 }
 
+// getGenDaoInput returns the input of "gen dao" like the command runs without arguments,
+// which is `data` overwritten by the single configuration and filled with the default values of options.
+// The configuration array is resolved by cGen.Dao for each of its items.
+func getGenDaoInput(ctx context.Context, data g.Map) (in cGenDaoInput, err error) {
+	if g.Cfg().Available(ctx) {
+		if v := g.Cfg().MustGet(ctx, cGenDaoConfig); !v.IsEmpty() && !v.IsSlice() {
+			for key, value := range v.Map() {
+				data[key] = value
+			}
+		}
+	}
+	tagFields, err := gstructs.TagFields(&in, []string{"d"})
+	if err != nil {
+		return
+	}
+	for _, field := range tagFields {
+		if foundKey, foundValue := gutil.MapPossibleItemByKey(data, field.Name()); foundKey == "" {
+			data[field.Name()] = field.TagValue
+		} else if foundValue == nil || gconv.String(foundValue) == "" {
+			data[foundKey] = field.TagValue
+		}
+	}
+	err = gconv.Struct(data, &in)
+	return
+}
+
 // doGenDaoForArray implements the "gen dao" command for configuration array.
 func doGenDaoForArray(ctx context.Context, index int, in cGenDaoInput) {
 	var (
@@ -215,19 +340,7 @@ func doGenDaoForArray(ctx context.Context, index int, in cGenDaoInput) {
 	}
 
 	// It uses user passed database configuration.
-	if in.Link != "" {
-		tempGroup := gtime.TimestampNanoStr()
-		match, _ := gregex.MatchString(`([a-z]+):(.+)`, in.Link)
-		if len(match) == 3 {
-			gdb.AddConfigNode(tempGroup, gdb.ConfigNode{
-				Type: gstr.Trim(match[1]),
-				Link: gstr.Trim(match[2]),
-			})
-			db, _ = gdb.Instance(tempGroup)
-		}
-	} else {
-		db = g.DB(in.Group)
-	}
+	db = getDatabase(in.Link, in.Group)
 	if db == nil {
 		mlog.Fatal("database initialization failed")
 	}
@@ -249,50 +362,151 @@ func doGenDaoForArray(ctx context.Context, index int, in cGenDaoInput) {
 		}
 		tableNames = array.Slice()
 	}
+	// Sharded tables collapsing.
+	shardings, err := parseDaoShardings(in.Sharding)
+	if err != nil {
+		mlog.Fatalf(`%+v`, err)
+	}
+	tableNames, shardTables := collapseShardedTables(ctx, db, tableNames, shardings)
+
+	// All the generated files are recorded in the manifest,
+	// it is a full generating if no tables are specified.
+	var (
+		scope       = getGeneratedManifestScope("gen dao", in.Path, in.Link, in.Group)
+		cache       = loadDaoCache()
+		cachedCount = 0
+	)
+	beginGeneratedManifest(scope)
+	defer endGeneratedManifest(in.Tables == "")
 
 	// Generating dao & model go files one by one according to given table name.
-	newTableNames := make([]string, len(tableNames))
-	for i, tableName := range tableNames {
+	for _, tableName := range tableNames {
 		newTableName := tableName
 		for _, v := range removePrefixArray {
 			newTableName = gstr.TrimLeftStr(newTableName, v, 1)
 		}
 		newTableName = in.Prefix + newTableName
-		newTableNames[i] = newTableName
-		// Dao.
-		generateDao(ctx, db, cGenDaoInternalInput{
+		tableInput := cGenDaoInternalInput{
 			cGenDaoInput: in,
 			TableName:    tableName,
 			NewTableName: newTableName,
 			ModName:      modName,
-		})
+			DbType:       db.GetConfig().Type,
+			ShardTables:  shardTables,
+			Shard:        shardTables[tableName],
+		}
+		// The table is skipped if neither its metadata nor the generating changes since last generating.
+		fieldMap, err := getTableFieldsForDao(ctx, db, tableName, tableInput)
+		if err != nil {
+			mlog.Fatalf("fetching tables fields failed for table '%s':\n%v", tableName, err)
+		}
+		hash := getDaoTableHash(fieldMap, tableInput)
+		if !in.All && isDaoTableCached(cache, scope, tableName, hash) {
+			keepGeneratedFiles(cache.Scopes[scope][tableName].Files)
+			cachedCount++
+			continue
+		}
+		fileIndex := len(currentManifestFiles)
+		// Dao.
+		generateDao(ctx, db, tableInput)
+		// Do.
+		generateDo(ctx, db, []string{tableName}, []string{newTableName}, tableInput)
+		// Entity.
+		generateEntity(ctx, db, []string{tableName}, []string{newTableName}, tableInput)
+
+		// The hash is computed again as the generating may create the dao index file which is hashed for mock.
+		// The dao index file is not recorded in the manifest as it is for custom methods,
+		// but it is cached as well, so that the table is regenerated if it is deleted.
+		hash = getDaoTableHash(fieldMap, tableInput)
+		files := append([]string{}, currentManifestFiles[fileIndex:]...)
+		files = append(files, getGeneratedManifestPath(
+			gfile.Join(in.Path, defaultDaoPath, getDaoFileName(newTableName)+".go"),
+		))
+		setDaoTableCache(cache, scope, tableName, hash, files)
 	}
-	// Do.
-	generateDo(ctx, db, tableNames, newTableNames, cGenDaoInternalInput{
+	// Shared files of all DAOs, which are generated once for each generating.
+	sharedInput := cGenDaoInternalInput{
 		cGenDaoInput: in,
 		ModName:      modName,
-	})
-	// Entity.
-	generateEntity(ctx, db, tableNames, newTableNames, cGenDaoInternalInput{
-		cGenDaoInput: in,
-		ModName:      modName,
-	})
+		DbType:       db.GetConfig().Type,
+	}
+	if in.GroupResolver {
+		generateDaoGroupResolver(sharedInput)
+	}
+	if in.TypedColumns {
+		generateDaoTypedColumnTypes(sharedInput)
+	}
+	if in.Tables == "" {
+		pruneDaoTableCache(cache, scope, tableNames)
+	}
+	// The cache is saved once after all tables are generated, which is fast for large schemas,
+	// and after the generated files are written, so that no table is cached if the writing fails.
+	flushGeneratedFiles()
+	saveDaoCache(cache)
+	if cachedCount > 0 {
+		mlog.Printf(`%d tables are skipped as they are not changed, use "--all" to regenerate them`, cachedCount)
+	}
+	// Stored procedures and functions.
+	if in.Routines != "" {
+		generateDaoRoutine(ctx, db, sharedInput)
+	}
+}
+
+// getDaoFileName returns the go file name without extension of dao for table `newTableName`.
+func getDaoFileName(newTableName string) string {
+	fileName := gstr.Trim(gstr.CaseSnake(newTableName), "-_.")
+	if len(fileName) > 5 && fileName[len(fileName)-5:] == "_test" {
+		// Add suffix to avoid the table name which contains "_test",
+		// which would make the go file a testing file.
+		fileName += "_table"
+	}
+	return fileName
 }
 
 // generateDaoContentFile generates the dao and model content of given table.
 func generateDao(ctx context.Context, db gdb.DB, in cGenDaoInternalInput) {
 	// Generating table data preparing.
-	fieldMap, err := db.TableFields(ctx, in.TableName)
+	fieldMap, err := getTableFieldsForDao(ctx, db, in.TableName, in)
 	if err != nil {
 		mlog.Fatalf("fetching tables fields failed for table '%s':\n%v", in.TableName, err)
 	}
 	var (
-		dirRealPath             = gfile.RealPath(in.Path)
 		dirPathDao              = gfile.Join(in.Path, defaultDaoPath)
 		tableNameCamelCase      = gstr.CaseCamel(in.NewTableName)
 		tableNameCamelLowerCase = gstr.CaseCamelLower(in.NewTableName)
-		tableNameSnakeCase      = gstr.CaseSnake(in.NewTableName)
-		importPrefix            = in.ImportPrefix
+		importPrefix            = getDaoImportPrefix(in)
+		fileName                = getDaoFileName(in.NewTableName)
+	)
+
+	// dao - index
+	generateDaoIndex(tableNameCamelCase, tableNameCamelLowerCase, importPrefix, dirPathDao, fileName, in)
+
+	// dao - internal
+	generateDaoInternal(tableNameCamelCase, tableNameCamelLowerCase, importPrefix, dirPathDao, fileName, fieldMap, in)
+
+	// dao - typed columns
+	if in.TypedColumns {
+		generateDaoTypedColumn(tableNameCamelCase, tableNameCamelLowerCase, dirPathDao, fileName, fieldMap, in)
+	}
+
+	// dao - shard resolving
+	if in.Shard != nil {
+		generateDaoShard(tableNameCamelCase, tableNameCamelLowerCase, dirPathDao, fileName, in)
+	}
+
+	// dao - interface and fake
+	if in.Mock && in.Shard != nil {
+		mlog.Printf(`table "%s" is sharded, ignore generating its DAO interface and fake`, in.TableName)
+	} else if in.Mock {
+		generateDaoMock(tableNameCamelCase, importPrefix, dirPathDao, fileName, fieldMap, in)
+	}
+}
+
+// getDaoImportPrefix returns the import prefix of the generated dao package.
+func getDaoImportPrefix(in cGenDaoInternalInput) string {
+	var (
+		dirRealPath  = gfile.RealPath(in.Path)
+		importPrefix = in.ImportPrefix
 	)
 	if importPrefix == "" {
 		if dirRealPath == "" {
@@ -306,19 +520,7 @@ func generateDao(ctx context.Context, db gdb.DB, in cGenDaoInternalInput) {
 		importPrefix = gstr.Join(g.SliceStr{in.ModName, importPrefix, defaultDaoPath}, "/")
 		importPrefix, _ = gregex.ReplaceString(`\/{2,}`, `/`, gstr.Trim(importPrefix, "/"))
 	}
-
-	fileName := gstr.Trim(tableNameSnakeCase, "-_.")
-	if len(fileName) > 5 && fileName[len(fileName)-5:] == "_test" {
-		// Add suffix to avoid the table name which contains "_test",
-		// which would make the go file a testing file.
-		fileName += "_table"
-	}
-
-	// dao - index
-	generateDaoIndex(tableNameCamelCase, tableNameCamelLowerCase, importPrefix, dirPathDao, fileName, in)
-
-	// dao - internal
-	generateDaoInternal(tableNameCamelCase, tableNameCamelLowerCase, importPrefix, dirPathDao, fileName, fieldMap, in)
+	return importPrefix
 }
 
 func generateDo(ctx context.Context, db gdb.DB, tableNames, newTableNames []string, in cGenDaoInternalInput) {
@@ -331,7 +533,7 @@ func generateDo(ctx context.Context, db gdb.DB, tableNames, newTableNames []stri
 	// Model content.
 	for i, tableName := range tableNames {
 		in.TableName = tableName
-		fieldMap, err := db.TableFields(ctx, tableName)
+		fieldMap, err := getTableFieldsForDao(ctx, db, tableName, in)
 		if err != nil {
 			mlog.Fatalf("fetching tables fields failed for table '%s':\n%v", in.TableName, err)
 		}
@@ -361,12 +563,12 @@ func generateDo(ctx context.Context, db gdb.DB, tableNames, newTableNames []stri
 			tableName,
 			gstr.CaseCamel(newTableName),
 			structDefinition,
+			getFieldDirectiveImports(fieldMap),
 		)
-		err = gfile.PutContents(doFilePath, strings.TrimSpace(modelContent))
+		err = putGeneratedContents(doFilePath, strings.TrimSpace(modelContent), in.Force)
 		if err != nil {
 			mlog.Fatalf("writing content to '%s' failed: %v", doFilePath, err)
 		} else {
-			utils.GoFmt(doFilePath)
 			mlog.Print("generated:", doFilePath)
 		}
 	}
@@ -379,7 +581,7 @@ func generateEntity(ctx context.Context, db gdb.DB, tableNames, newTableNames []
 
 	// Model content.
 	for i, tableName := range tableNames {
-		fieldMap, err := db.TableFields(ctx, tableName)
+		fieldMap, err := getTableFieldsForDao(ctx, db, tableName, in)
 		if err != nil {
 			mlog.Fatalf("fetching tables fields failed for table '%s':\n%v", in.TableName, err)
 		}
@@ -395,19 +597,21 @@ func generateEntity(ctx context.Context, db gdb.DB, tableNames, newTableNames []
 					FieldMap:             fieldMap,
 					IsDo:                 false,
 				}),
+				getFieldDirectiveImports(fieldMap),
 			)
 		)
-		err = gfile.PutContents(entityFilePath, strings.TrimSpace(entityContent))
+		err = putGeneratedContents(entityFilePath, strings.TrimSpace(entityContent), in.Force)
 		if err != nil {
 			mlog.Fatalf("writing content to '%s' failed: %v", entityFilePath, err)
 		} else {
-			utils.GoFmt(entityFilePath)
 			mlog.Print("generated:", entityFilePath)
 		}
 	}
 }
 
-func getImportPartContent(source string, isDo bool) string {
+// getImportPartContent returns the import content for go file `source`.
+// The `imports` are the import paths of custom types, which are imported only if they are used in `source`.
+func getImportPartContent(source string, isDo bool, imports ...string) string {
 	var (
 		packageImportsArray = garray.NewStrArray()
 	)
@@ -428,6 +632,21 @@ func getImportPartContent(source string, isDo bool) string {
 		packageImportsArray.Append(`"github.com/gogf/gf/v2/encoding/gjson"`)
 	}
 
+	// Custom types of "@type" directive.
+	for _, v := range imports {
+		var (
+			name       = getImportPackageName(v)
+			importLine = `"` + v + `"`
+		)
+		// The import is aliased if its package name is not the last element of path, like ".../uuid/v5".
+		if name != gfile.Basename(v) {
+			importLine = name + " " + importLine
+		}
+		if strings.Contains(source, name+".") && !packageImportsArray.Contains(importLine) {
+			packageImportsArray.Append(importLine)
+		}
+	}
+
 	// Generate and write content to golang file.
 	packageImportsStr := ""
 	if packageImportsArray.Len() > 0 {
@@ -436,10 +655,10 @@ func getImportPartContent(source string, isDo bool) string {
 	return packageImportsStr
 }
 
-func generateEntityContent(tableName, tableNameCamelCase, structDefine string) string {
+func generateEntityContent(tableName, tableNameCamelCase, structDefine string, imports []string) string {
 	entityContent := gstr.ReplaceByMap(consts.TemplateGenDaoEntityContent, g.MapStrStr{
 		tplVarTableName:          tableName,
-		tplVarPackageImports:     getImportPartContent(structDefine, false),
+		tplVarPackageImports:     getImportPartContent(structDefine, false, imports...),
 		tplVarTableNameCamelCase: tableNameCamelCase,
 		tplVarStructDefine:       structDefine,
 	})
@@ -447,10 +666,10 @@ func generateEntityContent(tableName, tableNameCamelCase, structDefine string) s
 	return entityContent
 }
 
-func generateDoContent(tableName, tableNameCamelCase, structDefine string) string {
+func generateDoContent(tableName, tableNameCamelCase, structDefine string, imports []string) string {
 	doContent := gstr.ReplaceByMap(consts.TemplateGenDaoDoContent, g.MapStrStr{
 		tplVarTableName:          tableName,
-		tplVarPackageImports:     getImportPartContent(structDefine, true),
+		tplVarPackageImports:     getImportPartContent(structDefine, true, imports...),
 		tplVarTableNameCamelCase: tableNameCamelCase,
 		tplVarStructDefine:       structDefine,
 	})
@@ -460,20 +679,18 @@ func generateDoContent(tableName, tableNameCamelCase, structDefine string) strin
 
 func generateDaoIndex(tableNameCamelCase, tableNameCamelLowerCase, importPrefix, dirPathDao, fileName string, in cGenDaoInternalInput) {
 	path := gfile.Join(dirPathDao, fileName+".go")
-	if in.OverwriteDao || !gfile.Exists(path) {
-		indexContent := gstr.ReplaceByMap(getTplDaoIndexContent(""), g.MapStrStr{
-			tplVarImportPrefix:            importPrefix,
-			tplVarTableName:               in.TableName,
-			tplVarTableNameCamelCase:      tableNameCamelCase,
-			tplVarTableNameCamelLowerCase: tableNameCamelLowerCase,
-		})
-		indexContent = replaceDefaultVar(indexContent)
-		if err := gfile.PutContents(path, strings.TrimSpace(indexContent)); err != nil {
-			mlog.Fatalf("writing content to '%s' failed: %v", path, err)
-		} else {
-			utils.GoFmt(path)
-			mlog.Print("generated:", path)
-		}
+	indexContent := gstr.ReplaceByMap(getTplDaoIndexContent(""), g.MapStrStr{
+		tplVarImportPrefix:            importPrefix,
+		tplVarTableName:               in.TableName,
+		tplVarTableNameCamelCase:      tableNameCamelCase,
+		tplVarTableNameCamelLowerCase: tableNameCamelLowerCase,
+	})
+	indexContent = replaceDefaultVar(indexContent)
+	// The dao index file is for custom codes, which is created only if it does not exist.
+	if written, err := putCustomContents(path, strings.TrimSpace(indexContent), in.OverwriteDao); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+	} else if written {
+		mlog.Print("generated:", path)
 	}
 }
 
@@ -483,7 +700,10 @@ func generateDaoInternal(
 	fieldMap map[string]*gdb.TableField,
 	in cGenDaoInternalInput,
 ) {
-	path := gfile.Join(dirPathDao, "internal", fileName+".go")
+	var (
+		path                  = gfile.Join(dirPathDao, "internal", fileName+".go")
+		dbMethod, groupMethod = getDaoGroupMethods(tableNameCamelCase, in)
+	)
 	modelContent := gstr.ReplaceByMap(getTplDaoInternalContent(""), g.MapStrStr{
 		tplVarImportPrefix:            importPrefix,
 		tplVarTableName:               in.TableName,
@@ -492,12 +712,15 @@ func generateDaoInternal(
 		tplVarTableNameCamelLowerCase: tableNameCamelLowerCase,
 		tplVarColumnDefine:            gstr.Trim(generateColumnDefinitionForDao(fieldMap)),
 		tplVarColumnNames:             gstr.Trim(generateColumnNamesForDao(fieldMap)),
+		tplVarDbMethod:                dbMethod,
+		tplVarGroupMethod:             groupMethod,
+		tplVarCtxMethods:              getDaoCtxMethods(tableNameCamelCase, in),
+		tplVarDbCall:                  getDaoDbCall(in),
 	})
 	modelContent = replaceDefaultVar(modelContent)
-	if err := gfile.PutContents(path, strings.TrimSpace(modelContent)); err != nil {
+	if err := putGeneratedContents(path, strings.TrimSpace(modelContent), in.Force); err != nil {
 		mlog.Fatalf("writing content to '%s' failed: %v", path, err)
 	} else {
-		utils.GoFmt(path)
 		mlog.Print("generated:", path)
 	}
 }
@@ -548,91 +771,109 @@ func generateStructDefinition(in generateStructDefinitionInput) string {
 // generateStructFieldForModel generates and returns the attribute definition for specified field.
 func generateStructFieldDefinition(field *gdb.TableField, in generateStructDefinitionInput) []string {
 	var (
-		typeName string
-		jsonTag  = getJsonTagFromCase(field.Name, in.JsonCase)
+		typeName   string
+		directives = parseFieldDirectives(field.Comment)
+		jsonTag    = directives.JsonTag(getJsonTagFromCase(field.Name, in.JsonCase))
 	)
 	t, _ := gregex.ReplaceString(`\(.+\)`, "", field.Type)
 	t = gstr.Split(gstr.Trim(t), " ")[0]
 	t = gstr.ToLower(t)
-	switch t {
-	case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
-		typeName = "[]byte"
-
-	case "bit", "int", "int2", "tinyint", "small_int", "smallint", "medium_int", "mediumint", "serial":
-		if gstr.ContainsI(field.Type, "unsigned") {
-			typeName = "uint"
-		} else {
-			typeName = "int"
-		}
-
-	case "int4", "int8", "big_int", "bigint", "bigserial":
-		if gstr.ContainsI(field.Type, "unsigned") {
-			typeName = "uint64"
-		} else {
-			typeName = "int64"
-		}
+	// Dialect specific type mapping takes priority of the common one.
+	if typeName = getDialectGoType(in.DbType, field, in.StdTime, in.GJsonSupport); typeName == "" {
+		switch t {
+		case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
+			typeName = "[]byte"
 
-	case "real":
-		typeName = "float32"
+		case "bit", "int", "int2", "tinyint", "small_int", "smallint", "medium_int", "mediumint", "serial":
+			if gstr.ContainsI(field.Type, "unsigned") {
+				typeName = "uint"
+			} else {
+				typeName = "int"
+			}
 
-	case "float", "double", "decimal", "smallmoney", "numeric":
-		typeName = "float64"
+		case "int4", "int8", "big_int", "bigint", "bigserial":
+			if gstr.ContainsI(field.Type, "unsigned") {
+				typeName = "uint64"
+			} else {
+				typeName = "int64"
+			}
 
-	case "bool":
-		typeName = "bool"
+		case "real":
+			typeName = "float32"
 
-	case "datetime", "timestamp", "date", "time":
-		if in.StdTime {
-			typeName = "time.Time"
-		} else {
-			typeName = "*gtime.Time"
-		}
-	case "json", "jsonb":
-		if in.GJsonSupport {
-			typeName = "*gjson.Json"
-		} else {
-			typeName = "string"
-		}
-	default:
-		// Automatically detect its data type.
-		switch {
-		case strings.Contains(t, "int"):
-			typeName = "int"
-		case strings.Contains(t, "text") || strings.Contains(t, "char"):
-			typeName = "string"
-		case strings.Contains(t, "float") || strings.Contains(t, "double"):
+		case "float", "double", "decimal", "smallmoney", "numeric":
 			typeName = "float64"
-		case strings.Contains(t, "bool"):
+
+		case "bool":
 			typeName = "bool"
-		case strings.Contains(t, "binary") || strings.Contains(t, "blob"):
-			typeName = "[]byte"
-		case strings.Contains(t, "date") || strings.Contains(t, "time"):
+
+		case "datetime", "timestamp", "date", "time":
 			if in.StdTime {
 				typeName = "time.Time"
 			} else {
 				typeName = "*gtime.Time"
 			}
+		case "json", "jsonb":
+			if in.GJsonSupport {
+				typeName = "*gjson.Json"
+			} else {
+				typeName = "string"
+			}
 		default:
-			typeName = "string"
+			// Automatically detect its data type.
+			switch {
+			case strings.Contains(t, "int"):
+				typeName = "int"
+			case strings.Contains(t, "text") || strings.Contains(t, "char"):
+				typeName = "string"
+			case strings.Contains(t, "float") || strings.Contains(t, "double"):
+				typeName = "float64"
+			case strings.Contains(t, "bool"):
+				typeName = "bool"
+			case strings.Contains(t, "binary") || strings.Contains(t, "blob"):
+				typeName = "[]byte"
+			case strings.Contains(t, "date") || strings.Contains(t, "time"):
+				if in.StdTime {
+					typeName = "time.Time"
+				} else {
+					typeName = "*gtime.Time"
+				}
+			default:
+				typeName = "string"
+			}
 		}
 	}
 
+	// The "@type" directive overwrites the type of field.
+	if directives.Type != "" {
+		typeName, _ = directives.GoType()
+	}
+
 	var (
 		tagKey = "`"
 		result = []string{
 			"    #" + gstr.CaseCamel(field.Name),
 			" #" + typeName,
 		}
-		descriptionTag = gstr.Replace(formatComment(field.Comment), `"`, `\"`)
+		comment        = formatComment(field.Comment)
+		descriptionTag = gstr.Replace(comment, `"`, `\"`)
+		jsonTagStr     = fmt.Sprintf(tagKey+`json:"%s"`, jsonTag)
 	)
+	// The "@enum" directive adds validation rule for entity.
+	if len(directives.Enum) > 0 && !in.IsDo {
+		jsonTagStr += fmt.Sprintf(` v:"in:%s"`, gstr.Join(directives.Enum, ","))
+	}
+	if directives.Deprecated {
+		comment = gstr.Trim("Deprecated: " + comment)
+	}
 
-	result = append(result, " #"+fmt.Sprintf(tagKey+`json:"%s"`, jsonTag))
+	result = append(result, " #"+jsonTagStr)
 	result = append(result, " #"+fmt.Sprintf(`description:"%s"`+tagKey, descriptionTag))
-	result = append(result, " #"+fmt.Sprintf(`// %s`, formatComment(field.Comment)))
+	result = append(result, " #"+fmt.Sprintf(`// %s`, comment))
 
 	for k, v := range result {
 		if in.NoJsonTag {
-			v, _ = gregex.ReplaceString(`json:".+"`, ``, v)
+			v, _ = gregex.ReplaceString(`json:"[^"]*"`, ``, v)
 		}
 		if !in.DescriptionTag {
 			v, _ = gregex.ReplaceString(`description:".*"`, ``, v)
@@ -645,8 +886,15 @@ func generateStructFieldDefinition(field *gdb.TableField, in generateStructDefin
 	return result
 }
 
-// formatComment formats the comment string to fit the golang code without any lines.
+// formatComment formats the comment string to fit the golang code without any lines,
+// in which the generating directives are removed.
 func formatComment(comment string) string {
+	return removeFieldDirectives(normalizeComment(comment))
+}
+
+// normalizeComment formats the comment string without any lines,
+// which keeps the generating directives, and is used for comparing or migrating database comments.
+func normalizeComment(comment string) string {
 	comment = gstr.ReplaceByArray(comment, g.SliceStr{
 		"\n", " ",
 		"\r", " ",
@@ -664,17 +912,11 @@ func generateColumnDefinitionForDao(fieldMap map[string]*gdb.TableField) string
 		names  = sortFieldKeyForDao(fieldMap)
 	)
 	for index, name := range names {
-		var (
-			field   = fieldMap[name]
-			comment = gstr.Trim(gstr.ReplaceByArray(field.Comment, g.SliceStr{
-				"\n", " ",
-				"\r", " ",
-			}))
-		)
+		field := fieldMap[name]
 		array[index] = []string{
 			"    #" + gstr.CaseCamel(field.Name),
 			" # " + "string",
-			" #" + fmt.Sprintf(`// %s`, comment),
+			" #" + fmt.Sprintf(`// %s`, formatComment(field.Comment)),
 		}
 	}
 	tw := tablewriter.NewWriter(buffer)
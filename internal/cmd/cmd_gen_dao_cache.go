@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/gogf/gf-cli/v2/internal/consts"
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/crypto/gmd5"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+const (
+	// generatedDaoCacheFile is the cache file in working directory,
+	// which records the schema hash of each table generated by "gen dao".
+	generatedDaoCacheFile = `.gf-gen-dao-cache.json`
+)
+
+type (
+	// daoCache records the generated tables of "gen dao" by the manifest scopes.
+	daoCache struct {
+		Scopes map[string]map[string]*daoCacheItem `json:"scopes"` // Generated tables by scope and table name.
+	}
+	// daoCacheItem is the record of a generated table.
+	daoCacheItem struct {
+		Hash  string   `json:"hash"`  // Hash of table metadata, generator options and templates.
+		Files []string `json:"files"` // Manifest paths of the files generated for the table.
+	}
+)
+
+// daoCacheTemplates are the templates affecting the generated files of table,
+// which are hashed so that the tables are regenerated if the templates change.
+var daoCacheTemplates = []string{
+	consts.TemplateDaoDaoIndexContent,
+	consts.TemplateDaoDaoInternalContent,
+	consts.TemplateGenDaoDoContent,
+	consts.TemplateGenDaoEntityContent,
+	consts.TemplateDaoDaoInternalMethodsContent,
+	consts.TemplateDaoDaoInterfaceContent,
+	consts.TemplateDaoDaoFakeContent,
+	consts.TemplateDaoDaoInternalShardContent,
+	consts.TemplateDaoDaoInternalCtxContent,
+	consts.TemplateDaoDaoInternalShardCtxContent,
+	consts.TemplateDaoTypedColumnContent,
+	consts.TemplateDaoTypedColumnTypeContent,
+	consts.TemplateDaoTypedColumnEqContent,
+	consts.TemplateDaoTypedColumnBetweenContent,
+	consts.TemplateDaoTypedColumnLikeContent,
+	consts.TemplateDaoDaoInternalTypedContent,
+	consts.TemplateDaoDaoInternalDbContent,
+	consts.TemplateDaoDaoInternalGroupContent,
+	consts.TemplateDaoDaoInternalDbResolverContent,
+	consts.TemplateDaoDaoInternalGroupResolverContent,
+	consts.TemplateDaoGroupResolverInternalContent,
+	consts.TemplateDaoGroupResolverIndexContent,
+}
+
+// getDaoTableHash returns the hash of table `in.TableName` with its fields `fieldMap`,
+// which changes if any of the table metadata, generator options, templates or tool version changes.
+// It also changes if the dao index file with custom methods changes when generating mocks.
+func getDaoTableHash(fieldMap map[string]*gdb.TableField, in cGenDaoInternalInput) string {
+	options := in
+	// The options selecting tables or not affecting generated content are ignored.
+	options.Tables = ""
+	options.TablesEx = ""
+	options.Routines = ""
+	options.Force = false
+	options.All = false
+	options.ShardTables = nil
+	data := g.Map{
+		"version":   consts.Version,
+		"templates": daoCacheTemplates,
+		"options":   options,
+		"fields":    fieldMap,
+	}
+	// The mock interface contains the custom methods of dao index file, which is hashed as well.
+	if in.Mock {
+		indexPath := gfile.Join(in.Path, defaultDaoPath, getDaoFileName(in.NewTableName)+".go")
+		if content, ok := getGeneratedContents(indexPath); ok {
+			data["index"] = getGeneratedContentChecksum(content)
+		}
+	}
+	content, err := json.Marshal(data)
+	if err != nil {
+		mlog.Fatalf(`encoding table "%s" for hashing failed: %v`, in.TableName, err)
+	}
+	return gmd5.MustEncryptBytes(content)
+}
+
+// isDaoTableCached checks whether table `tableName` of `scope` was generated with hash `hash`
+// and all its generated files exist.
+func isDaoTableCached(cache *daoCache, scope, tableName, hash string) bool {
+	item := cache.Scopes[scope][tableName]
+	if item == nil || item.Hash != hash || len(item.Files) == 0 {
+		return false
+	}
+	for _, path := range item.Files {
+		if !gfile.Exists(path) {
+			return false
+		}
+	}
+	return true
+}
+
+// setDaoTableCache records the hash and generated files of table `tableName` of `scope`.
+func setDaoTableCache(cache *daoCache, scope, tableName, hash string, files []string) {
+	if cache.Scopes[scope] == nil {
+		cache.Scopes[scope] = make(map[string]*daoCacheItem)
+	}
+	cache.Scopes[scope][tableName] = &daoCacheItem{
+		Hash:  hash,
+		Files: files,
+	}
+}
+
+// pruneDaoTableCache removes the tables of `scope` that are not in `tableNames`,
+// which is called after full generating.
+func pruneDaoTableCache(cache *daoCache, scope string, tableNames []string) {
+	tableMap := make(map[string]bool, len(tableNames))
+	for _, tableName := range tableNames {
+		tableMap[tableName] = true
+	}
+	for tableName := range cache.Scopes[scope] {
+		if !tableMap[tableName] {
+			delete(cache.Scopes[scope], tableName)
+		}
+	}
+}
+
+// loadDaoCache loads and returns the cache from working directory.
+// It returns empty cache if the cache file is invalid, as the cache can always be rebuilt.
+func loadDaoCache() *daoCache {
+	cache := &daoCache{}
+	if gfile.Exists(generatedDaoCacheFile) {
+		if err := json.Unmarshal(gfile.GetBytes(generatedDaoCacheFile), cache); err != nil {
+			mlog.Printf(`invalid cache file "%s", all tables are regenerated: %v`, generatedDaoCacheFile, err)
+			cache = &daoCache{}
+		}
+	}
+	if cache.Scopes == nil {
+		cache.Scopes = make(map[string]map[string]*daoCacheItem)
+	}
+	return cache
+}
+
+// saveDaoCache saves the cache to working directory.
+func saveDaoCache(cache *daoCache) {
+	content, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		mlog.Fatalf(`encoding cache failed: %v`, err)
+	}
+	if err = gfile.PutBytes(generatedDaoCacheFile, content); err != nil {
+		mlog.Fatalf(`writing cache file "%s" failed: %v`, generatedDaoCacheFile, err)
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/gogf/gf-cli/v2/internal/consts"
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+const (
+	tplVarDbMethod        = `{TplDbMethod}`
+	tplVarGroupMethod     = `{TplGroupMethod}`
+	tplVarGroupMethodArgs = `{TplGroupMethodArgs}`
+	tplVarDbCall          = `{TplDbCall}`
+	// groupResolverFileName is the file name of group resolver shared by all DAOs.
+	groupResolverFileName = `group_resolver.go`
+)
+
+// getDaoGroupMethods returns the DB and Group methods of DAO `daoNameCamelCase`,
+// which resolve the database group from context if the group resolver is enabled.
+func getDaoGroupMethods(daoNameCamelCase string, in cGenDaoInternalInput) (dbMethod, groupMethod string) {
+	dbMethod, groupMethod = consts.TemplateDaoDaoInternalDbContent, consts.TemplateDaoDaoInternalGroupContent
+	if in.GroupResolver {
+		dbMethod, groupMethod = consts.TemplateDaoDaoInternalDbResolverContent, consts.TemplateDaoDaoInternalGroupResolverContent
+	}
+	dbMethod = gstr.Trim(gstr.Replace(dbMethod, tplVarTableNameCamelCase, daoNameCamelCase))
+	groupMethod = gstr.Trim(gstr.Replace(groupMethod, tplVarTableNameCamelCase, daoNameCamelCase))
+	return
+}
+
+// getDaoGroupMethodArgs returns the arguments definition of DB and Group methods of DAO.
+func getDaoGroupMethodArgs(in cGenDaoInternalInput) string {
+	if in.GroupResolver {
+		return "ctx ...context.Context"
+	}
+	return ""
+}
+
+// getDaoDbCall returns the expression retrieving database object of DAO in methods having `ctx`.
+func getDaoDbCall(in cGenDaoInternalInput) string {
+	if in.GroupResolver {
+		return "dao.DB(ctx)"
+	}
+	return "dao.DB()"
+}
+
+// generateDaoGroupResolver generates the group resolver shared by all DAOs,
+// which are the same for all tables, so it is generated once for each generating.
+func generateDaoGroupResolver(in cGenDaoInternalInput) {
+	var (
+		importPrefix = getDaoImportPrefix(in)
+		dirPathDao   = gfile.Join(in.Path, defaultDaoPath)
+	)
+	for path, template := range map[string]string{
+		gfile.Join(dirPathDao, "internal", groupResolverFileName): consts.TemplateDaoGroupResolverInternalContent,
+		gfile.Join(dirPathDao, groupResolverFileName):             consts.TemplateDaoGroupResolverIndexContent,
+	} {
+		content := replaceDefaultVar(gstr.ReplaceByMap(template, g.MapStrStr{
+			tplVarImportPrefix: importPrefix,
+		}))
+		if err := putGeneratedContents(path, strings.TrimSpace(content), in.Force); err != nil {
+			mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"github.com/gogf/gf-cli/v2/internal/consts"
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/container/garray"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+const (
+	tplVarEntityImport      = `{TplEntityImport}`
+	tplVarPrimaryKey        = `{TplPrimaryKey}`
+	tplVarPrimaryKeyType    = `{TplPrimaryKeyType}`
+	tplVarInsertContent     = `{TplInsertContent}`
+	tplVarCustomMethods     = `{TplCustomMethods}`
+	tplVarFakeFields        = `{TplFakeFields}`
+	tplVarFakeInsertContent = `{TplFakeInsertContent}`
+)
+
+// generateDaoMock generates the interface of DAO, the primary key methods of internal DAO
+// and the in-memory fake DAO for table.
+// It does nothing but prints a message if the table does not have a single primary key,
+// as the fake records are keyed by primary key.
+func generateDaoMock(
+	tableNameCamelCase, importPrefix string,
+	dirPathDao, fileName string,
+	fieldMap map[string]*gdb.TableField,
+	in cGenDaoInternalInput,
+) {
+	var primaryFields = make([]*gdb.TableField, 0)
+	for _, name := range sortFieldKeyForDao(fieldMap) {
+		if gstr.ContainsI(fieldMap[name].Key, "pri") {
+			primaryFields = append(primaryFields, fieldMap[name])
+		}
+	}
+	if len(primaryFields) != 1 {
+		mlog.Printf(
+			`table "%s" does not have a single primary key, ignore generating its DAO interface and fake`,
+			in.TableName,
+		)
+		return
+	}
+	var (
+		primaryField   = primaryFields[0]
+		primaryKey     = gstr.CaseCamel(primaryField.Name)
+		primaryKeyType = gstr.Trim(generateStructFieldDefinition(primaryField, generateStructDefinitionInput{
+			cGenDaoInternalInput: in,
+		})[1], " #")
+		isIntegerKey  = gstr.HasPrefix(primaryKeyType, "int") || gstr.HasPrefix(primaryKeyType, "uint")
+		insertContent = "_, err := dao.Ctx(ctx).Data(data).Insert()\nreturn err"
+		fakeFields    = ""
+		fakeInsert    = ""
+	)
+	if gstr.HasPrefix(primaryKeyType, "*") || gstr.HasPrefix(primaryKeyType, "[]") {
+		mlog.Printf(
+			`primary key type "%s" of table "%s" is not comparable, ignore generating its DAO interface and fake`,
+			primaryKeyType, in.TableName,
+		)
+		return
+	}
+	if isIntegerKey {
+		// The zero value of integer primary key is considered auto increment.
+		insertContent = gstr.ReplaceByMap(`if data.{TplPrimaryKey} == 0 {
+	id, err := dao.Ctx(ctx).FieldsEx(dao.columns.{TplPrimaryKey}).Data(data).InsertAndGetId()
+	if err != nil {
+		return err
+	}
+	data.{TplPrimaryKey} = {TplPrimaryKeyType}(id)
+	return nil
+}
+`+insertContent, g.MapStrStr{
+			tplVarPrimaryKey:     primaryKey,
+			tplVarPrimaryKeyType: primaryKeyType,
+		})
+		fakeFields = "lastKey " + primaryKeyType
+		fakeInsert = gstr.ReplaceByMap(`if data.{TplPrimaryKey} == 0 {
+	f.lastKey++
+	data.{TplPrimaryKey} = f.lastKey
+} else if data.{TplPrimaryKey} > f.lastKey {
+	f.lastKey = data.{TplPrimaryKey}
+}`, g.MapStrStr{
+			tplVarPrimaryKey: primaryKey,
+		})
+	}
+
+	var (
+		entityImport = gstr.TrimRightStr(importPrefix, defaultDaoPath)
+		indexPath    = gfile.Join(dirPathDao, fileName+".go")
+		replaceMap   = g.MapStrStr{
+			tplVarImportPrefix:       importPrefix,
+			tplVarEntityImport:       gstr.TrimRight(entityImport, "/") + "/" + defaultEntityPath,
+			tplVarTableName:          in.TableName,
+			tplVarGroupName:          in.Group,
+			tplVarTableNameCamelCase: tableNameCamelCase,
+			tplVarPrimaryKey:         primaryKey,
+			tplVarPrimaryKeyType:     primaryKeyType,
+			tplVarInsertContent:      insertContent,
+			tplVarFakeFields:         fakeFields,
+			tplVarFakeInsertContent:  fakeInsert,
+			tplVarGroupMethodArgs:    getDaoGroupMethodArgs(in),
+		}
+	)
+	// Custom methods that are defined in the dao index file.
+	replaceMap[tplVarCustomMethods], replaceMap[tplVarPackageImports] = getDaoCustomMethods(
+		indexPath,
+		gstr.CaseCamelLower(tableNameCamelCase)+"Dao",
+		importPrefix+"/internal",
+		replaceMap[tplVarEntityImport],
+	)
+
+	for _, item := range [][2]string{
+		{gfile.Join(dirPathDao, "internal", fileName+"_methods.go"), consts.TemplateDaoDaoInternalMethodsContent},
+		{gfile.Join(dirPathDao, fileName+"_interface.go"), consts.TemplateDaoDaoInterfaceContent},
+		{gfile.Join(dirPathDao, "fake", fileName+".go"), consts.TemplateDaoDaoFakeContent},
+	} {
+		var (
+			path    = item[0]
+			content = replaceDefaultVar(gstr.ReplaceByMap(item[1], replaceMap))
+		)
+		if err := putGeneratedContents(path, strings.TrimSpace(content), in.Force); err != nil {
+			mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+		} else {
+			mlog.Print("generated:", path)
+		}
+	}
+}
+
+// getDaoCustomMethods parses the dao index file and returns the method declarations for interface
+// of the exported methods defined on `receiverName` with value receiver,
+// along with the imports that the method declarations depend on, excluding `importedPaths`.
+func getDaoCustomMethods(indexPath, receiverName string, importedPaths ...string) (methods string, imports string) {
+	// The dao index file might be pending in current generating run.
+	content, ok := getGeneratedContents(indexPath)
+	if !ok {
+		return "", ""
+	}
+	var (
+		fileSet     = token.NewFileSet()
+		file, err   = parser.ParseFile(fileSet, indexPath, content, parser.ParseComments)
+		methodArray = garray.NewStrArray()
+		importArray = garray.NewStrArray()
+	)
+	if err != nil {
+		mlog.Fatalf(`parsing dao file "%s" failed: %+v`, indexPath, err)
+	}
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) != 1 || !funcDecl.Name.IsExported() {
+			continue
+		}
+		// Pointer receiver methods are not in the method set of the DAO variable.
+		if ident, ok := funcDecl.Recv.List[0].Type.(*ast.Ident); !ok || ident.Name != receiverName {
+			continue
+		}
+		buffer := bytes.NewBuffer(nil)
+		if err = printer.Fprint(buffer, fileSet, funcDecl.Type); err != nil {
+			mlog.Fatalf(`parsing method "%s" of dao file "%s" failed: %+v`, funcDecl.Name.Name, indexPath, err)
+		}
+		method := funcDecl.Name.Name + gstr.TrimLeftStr(buffer.String(), "func")
+		if funcDecl.Doc != nil {
+			for _, comment := range funcDecl.Doc.List {
+				methodArray.Append(comment.Text)
+			}
+		}
+		methodArray.Append(method)
+	}
+	methods = methodArray.Join("\n")
+	for _, importSpec := range file.Imports {
+		var (
+			path = gstr.Trim(importSpec.Path.Value, `"`)
+			name = gfile.Basename(path)
+		)
+		if importSpec.Name != nil {
+			name = importSpec.Name.Name
+		}
+		// Already imported by template.
+		if path == "context" || path == "github.com/gogf/gf/v2/database/gdb" || gstr.InArray(importedPaths, path) {
+			continue
+		}
+		if gstr.Contains(methods, name+".") {
+			importArray.Append(fmt.Sprintf(`%s "%s"`, name, path))
+		}
+	}
+	imports = importArray.Join("\n")
+	return
+}
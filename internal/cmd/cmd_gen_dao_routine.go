@@ -0,0 +1,449 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/gogf/gf-cli/v2/internal/consts"
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+const (
+	tplVarRoutineNameCamelCase      = `{TplRoutineNameCamelCase}`
+	tplVarRoutineNameCamelLowerCase = `{TplRoutineNameCamelLowerCase}`
+	tplVarRoutineDefine             = `{TplRoutineDefine}`
+)
+
+const (
+	routineTypeFunction = "FUNCTION"
+	routineParamModeIn  = "IN"
+	routineParamModeOut = "OUT"
+)
+
+// routineReservedNames are the identifiers used in generated routine methods,
+// which cannot be used as parameter names like the golang keywords.
+var routineReservedNames = map[string]bool{
+	"ctx":     true,
+	"err":     true,
+	"result":  true,
+	"value":   true,
+	"all":     true,
+	"one":     true,
+	"tx":      true,
+	"dao":     true,
+	"context": true,
+	"gdb":     true,
+}
+
+type (
+	// routineDefinition is the definition of stored procedure or function.
+	routineDefinition struct {
+		Name       string              // Name of the routine.
+		Specific   string              // Specific name of the routine, which identifies the overloaded routines.
+		Type       string              // Type of the routine, PROCEDURE or FUNCTION.
+		ReturnType string              // Return type of function.
+		Comment    string              // Comment of the routine.
+		Params     []*routineParameter // Parameters of the routine.
+	}
+	// routineParameter is the parameter definition of stored procedure or function.
+	routineParameter struct {
+		Routine  string // Specific name of the routine.
+		Position int    // Position of the parameter, starting from 1.
+		Mode     string // Mode of the parameter, IN, OUT or INOUT.
+		Name     string // Name of the parameter.
+		Type     string // Database type of the parameter.
+	}
+	// routineGoParameter is the golang definition of routine parameter.
+	routineGoParameter struct {
+		*routineParameter
+		GoName  string // Golang argument name.
+		GoField string // Golang result struct field name.
+		GoType  string // Golang type.
+	}
+)
+
+// loadDatabaseRoutines retrieves and returns the stored procedures and functions of current database,
+// which are sorted by name.
+func loadDatabaseRoutines(ctx context.Context, db gdb.DB) ([]*routineDefinition, error) {
+	var routineSql, paramSql string
+	switch db.GetConfig().Type {
+	case "mysql", "mariadb", "tidb":
+		routineSql = `SELECT ROUTINE_NAME AS name, SPECIFIC_NAME AS specific, ROUTINE_TYPE AS type, ` +
+			`IFNULL(DTD_IDENTIFIER,'') AS return_type, ROUTINE_COMMENT AS comment ` +
+			`FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA=DATABASE() ORDER BY ROUTINE_NAME`
+		paramSql = `SELECT SPECIFIC_NAME AS routine, ORDINAL_POSITION AS position, IFNULL(PARAMETER_MODE,'') AS mode, ` +
+			`IFNULL(PARAMETER_NAME,'') AS name, DTD_IDENTIFIER AS type ` +
+			`FROM information_schema.PARAMETERS WHERE SPECIFIC_SCHEMA=DATABASE() AND ORDINAL_POSITION>0 ` +
+			`ORDER BY SPECIFIC_NAME, ORDINAL_POSITION`
+	case "pgsql":
+		routineSql = `SELECT routine_name AS name, specific_name AS specific, UPPER(routine_type) AS type, ` +
+			`COALESCE(data_type,'') AS return_type, '' AS comment ` +
+			`FROM information_schema.routines WHERE specific_schema=current_schema() ORDER BY routine_name`
+		paramSql = `SELECT specific_name AS routine, ordinal_position AS position, COALESCE(parameter_mode,'') AS mode, ` +
+			`COALESCE(parameter_name,'') AS name, data_type AS type ` +
+			`FROM information_schema.parameters WHERE specific_schema=current_schema() ` +
+			`ORDER BY specific_name, ordinal_position`
+	default:
+		mlog.Printf(`stored procedures and functions generating is not supported for database type "%s"`, db.GetConfig().Type)
+		return nil, nil
+	}
+	var (
+		routines []*routineDefinition
+		params   []*routineParameter
+	)
+	result, err := db.GetAll(ctx, routineSql)
+	if err != nil {
+		return nil, err
+	}
+	if err = result.Structs(&routines); err != nil {
+		return nil, err
+	}
+	if result, err = db.GetAll(ctx, paramSql); err != nil {
+		return nil, err
+	}
+	if err = result.Structs(&params); err != nil {
+		return nil, err
+	}
+	routineMap := make(map[string]*routineDefinition)
+	for _, routine := range routines {
+		routineMap[routine.Specific] = routine
+	}
+	for _, param := range params {
+		if routine, ok := routineMap[param.Routine]; ok {
+			if param.Mode == "" {
+				param.Mode = routineParamModeIn
+			}
+			if param.Name == "" {
+				param.Name = fmt.Sprintf("arg%d", param.Position)
+			}
+			routine.Params = append(routine.Params, param)
+		}
+	}
+	return routines, nil
+}
+
+// generateDaoRoutine generates the DAO wrapping the stored procedures and functions matching `in.Routines`.
+func generateDaoRoutine(ctx context.Context, db gdb.DB, in cGenDaoInternalInput) {
+	routines, err := loadDatabaseRoutines(ctx, db)
+	if err != nil {
+		mlog.Fatalf("fetching stored procedures and functions failed: %+v", err)
+	}
+	var (
+		buffer       = bytes.NewBuffer(nil)
+		names        = make([]string, 0, len(routines))
+		methodNames  = make(map[string]bool)
+		dirPathDao   = gfile.Join(in.Path, defaultDaoPath)
+		daoName      = "routine"
+		fileName     = "routine"
+		routineCount = 0
+	)
+	if in.Group != "" && in.Group != "default" {
+		daoName = in.Group + "_routine"
+		fileName = gstr.CaseSnake(daoName)
+	}
+	var (
+		daoNameCamelCase      = gstr.CaseCamel(daoName)
+		daoNameCamelLowerCase = gstr.CaseCamelLower(daoName)
+	)
+	for _, routine := range routines {
+		names = append(names, routine.Name)
+	}
+	names = filterTableNames(names, in.Routines, "")
+	for _, routine := range routines {
+		if !gstr.InArray(names, routine.Name) {
+			continue
+		}
+		methodName := gstr.CaseCamel(routine.Name)
+		if methodNames[methodName] {
+			mlog.Printf(`overloaded routine "%s" is ignored as its method name "%s" is used`, routine.Specific, methodName)
+			continue
+		}
+		methodNames[methodName] = true
+		buffer.WriteString(generateDaoRoutineMethod(daoNameCamelCase+"Dao", methodName, routine, in))
+		buffer.WriteString("\n")
+		routineCount++
+	}
+	if routineCount == 0 {
+		mlog.Printf(`no stored procedure or function matches "%s"`, in.Routines)
+		return
+	}
+	dbMethod, groupMethod := getDaoGroupMethods(daoNameCamelCase, in)
+	replaceMap := g.MapStrStr{
+		tplVarImportPrefix:              getDaoImportPrefix(in),
+		tplVarGroupName:                 in.Group,
+		tplVarRoutineNameCamelCase:      daoNameCamelCase,
+		tplVarRoutineNameCamelLowerCase: daoNameCamelLowerCase,
+		tplVarRoutineDefine:             gstr.Trim(buffer.String()),
+		tplVarDbMethod:                  dbMethod,
+		tplVarGroupMethod:               groupMethod,
+	}
+	// dao - index
+	indexPath := gfile.Join(dirPathDao, fileName+".go")
+	indexContent := gstr.ReplaceByMap(consts.TemplateDaoRoutineIndexContent, replaceMap)
+	if written, err := putCustomContents(indexPath, strings.TrimSpace(indexContent), in.OverwriteDao); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", indexPath, err)
+	} else if written {
+		mlog.Print("generated:", indexPath)
+	}
+	// dao - internal
+	path := gfile.Join(dirPathDao, "internal", fileName+".go")
+	content := replaceDefaultVar(gstr.ReplaceByMap(consts.TemplateDaoRoutineInternalContent, replaceMap))
+	if err = putGeneratedContents(path, strings.TrimSpace(content), in.Force); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+	} else {
+		mlog.Print("generated:", path)
+	}
+}
+
+// generateDaoRoutineMethod generates and returns the wrapping method of `routine` for DAO `daoName`.
+//
+// The wrapping methods are generated according to the routine:
+//  1. Function returning single value, it returns the typed value.
+//  2. Function with OUT parameters, like pgsql "RETURNS TABLE", it returns the typed result structs.
+//  3. Procedure with OUT/INOUT parameters, it returns the typed result struct of the parameters.
+//  4. Procedure without OUT/INOUT parameters, it returns the result sets of the procedure for mysql,
+//     or it returns only error for pgsql.
+func generateDaoRoutineMethod(daoName, methodName string, routine *routineDefinition, in cGenDaoInternalInput) string {
+	var (
+		buffer        = bytes.NewBuffer(nil)
+		inParams      = make([]*routineGoParameter, 0)
+		outParams     = make([]*routineGoParameter, 0)
+		arguments     = make([]string, 0)
+		argumentNames = make([]string, 0)
+		placeholders  = make([]string, 0)
+		resultName    = methodName + "Result"
+		isMysql       = in.DbType != "pgsql"
+		dbCall        = getDaoDbCall(in)
+		comment       = formatComment(routine.Comment)
+	)
+	for _, param := range routine.Params {
+		goParam := &routineGoParameter{
+			routineParameter: param,
+			GoName:           gstr.CaseCamelLower(param.Name),
+			GoField:          gstr.CaseCamel(param.Name),
+			GoType:           getRoutineGoType(param.Type, in),
+		}
+		if token.IsKeyword(goParam.GoName) || routineReservedNames[goParam.GoName] {
+			goParam.GoName += "Value"
+		}
+		if param.Mode != routineParamModeOut {
+			inParams = append(inParams, goParam)
+			arguments = append(arguments, goParam.GoName+" "+goParam.GoType)
+			argumentNames = append(argumentNames, goParam.GoName)
+		}
+		if param.Mode != routineParamModeIn {
+			outParams = append(outParams, goParam)
+		}
+	}
+	if comment == "" {
+		comment = fmt.Sprintf("calls %s %s.", gstr.ToLower(routine.Type), routine.Name)
+	}
+	methodArguments := gstr.Join(append([]string{"ctx context.Context"}, arguments...), ", ")
+	argumentValues := ""
+	if len(argumentNames) > 0 {
+		argumentValues = ", " + gstr.Join(argumentNames, ", ")
+	}
+
+	// Result struct of OUT parameters.
+	if len(outParams) > 0 {
+		buffer.WriteString(fmt.Sprintf("// %s is the result of %s %s.\n", resultName, gstr.ToLower(routine.Type), routine.Name))
+		buffer.WriteString(fmt.Sprintf("type %s struct {\n", resultName))
+		for _, param := range outParams {
+			buffer.WriteString(fmt.Sprintf(
+				"%s %s `json:\"%s\"`\n", param.GoField, param.GoType, getJsonTagFromCase(param.Name, in.JsonCase),
+			))
+		}
+		buffer.WriteString("}\n\n")
+	}
+	buffer.WriteString(fmt.Sprintf("// %s %s\n", methodName, comment))
+
+	switch {
+	// Function returning single value.
+	case routine.Type == routineTypeFunction && len(outParams) == 0 && routine.ReturnType != "" &&
+		!gstr.Equal(routine.ReturnType, "void") && !gstr.Equal(routine.ReturnType, "record"):
+		for range inParams {
+			placeholders = append(placeholders, "?")
+		}
+		returnType := getRoutineGoType(routine.ReturnType, in)
+		buffer.WriteString(fmt.Sprintf(
+			"func (dao *%s) %s(%s) (result %s, err error) {\n", daoName, methodName, methodArguments, returnType,
+		))
+		buffer.WriteString(fmt.Sprintf(
+			"value, err := %s.GetValue(ctx, `SELECT %s(%s)`%s)\n",
+			dbCall, routine.Name, gstr.Join(placeholders, ", "), argumentValues,
+		))
+		buffer.WriteString("if err != nil {\nreturn\n}\n")
+		buffer.WriteString(fmt.Sprintf("return %s, nil\n}\n", getRoutineValueConversion("value", returnType)))
+
+	// Function returning set, like pgsql "RETURNS TABLE".
+	case routine.Type == routineTypeFunction:
+		for range inParams {
+			placeholders = append(placeholders, "?")
+		}
+		resultType := "gdb.Result"
+		if len(outParams) > 0 {
+			resultType = "[]*" + resultName
+		}
+		buffer.WriteString(fmt.Sprintf(
+			"func (dao *%s) %s(%s) (result %s, err error) {\n", daoName, methodName, methodArguments, resultType,
+		))
+		if len(outParams) > 0 {
+			buffer.WriteString(fmt.Sprintf(
+				"all, err := %s.GetAll(ctx, `SELECT * FROM %s(%s)`%s)\n",
+				dbCall, routine.Name, gstr.Join(placeholders, ", "), argumentValues,
+			))
+			buffer.WriteString("if err != nil {\nreturn\n}\n")
+			buffer.WriteString("err = all.Structs(&result)\nreturn\n}\n")
+		} else {
+			buffer.WriteString(fmt.Sprintf(
+				"return %s.GetAll(ctx, `SELECT * FROM %s(%s)`%s)\n}\n",
+				dbCall, routine.Name, gstr.Join(placeholders, ", "), argumentValues,
+			))
+		}
+
+	// Mysql procedure with OUT/INOUT parameters, which are passed by session variables.
+	case isMysql && len(outParams) > 0:
+		var (
+			setStatements     = make([]string, 0)
+			selectVariables   = make([]string, 0)
+			setArgumentNames  = make([]string, 0)
+			callArgumentNames = make([]string, 0)
+		)
+		for _, param := range inParams {
+			if param.Mode == routineParamModeIn {
+				callArgumentNames = append(callArgumentNames, param.GoName)
+			}
+		}
+		for _, param := range routine.Params {
+			variable := "@" + routine.Name + "_" + param.Name
+			switch param.Mode {
+			case routineParamModeIn:
+				placeholders = append(placeholders, "?")
+			default:
+				placeholders = append(placeholders, variable)
+				selectVariables = append(selectVariables, fmt.Sprintf("%s AS '%s'", variable, param.Name))
+			}
+		}
+		for _, param := range outParams {
+			if param.Mode != routineParamModeOut {
+				setStatements = append(setStatements, "@"+routine.Name+"_"+param.Name+" = ?")
+				setArgumentNames = append(setArgumentNames, param.GoName)
+			}
+		}
+		buffer.WriteString(fmt.Sprintf(
+			"func (dao *%s) %s(%s) (result *%s, err error) {\n", daoName, methodName, methodArguments, resultName,
+		))
+		buffer.WriteString(fmt.Sprintf("err = %s.Transaction(ctx, func(ctx context.Context, tx *gdb.TX) error {\n", dbCall))
+		if len(setStatements) > 0 {
+			buffer.WriteString(fmt.Sprintf(
+				"if _, err := tx.Exec(`SET %s`, %s); err != nil {\nreturn err\n}\n",
+				gstr.Join(setStatements, ", "), gstr.Join(setArgumentNames, ", "),
+			))
+		}
+		callArguments := ""
+		if len(callArgumentNames) > 0 {
+			callArguments = ", " + gstr.Join(callArgumentNames, ", ")
+		}
+		buffer.WriteString(fmt.Sprintf(
+			"if _, err := tx.Exec(`CALL %s(%s)`%s); err != nil {\nreturn err\n}\n",
+			routine.Name, gstr.Join(placeholders, ", "), callArguments,
+		))
+		buffer.WriteString(fmt.Sprintf(
+			"one, err := tx.GetOne(`SELECT %s`)\nif err != nil {\nreturn err\n}\n",
+			gstr.Join(selectVariables, ", "),
+		))
+		buffer.WriteString("return one.Struct(&result)\n})\nreturn\n}\n")
+
+	// Pgsql procedure with OUT/INOUT parameters, which returns a row of the parameters.
+	case len(outParams) > 0:
+		for _, param := range routine.Params {
+			if param.Mode == routineParamModeOut {
+				placeholders = append(placeholders, "NULL")
+			} else {
+				placeholders = append(placeholders, "?")
+			}
+		}
+		buffer.WriteString(fmt.Sprintf(
+			"func (dao *%s) %s(%s) (result *%s, err error) {\n", daoName, methodName, methodArguments, resultName,
+		))
+		buffer.WriteString(fmt.Sprintf(
+			"one, err := %s.GetOne(ctx, `CALL %s(%s)`%s)\n",
+			dbCall, routine.Name, gstr.Join(placeholders, ", "), argumentValues,
+		))
+		buffer.WriteString("if err != nil {\nreturn\n}\n")
+		buffer.WriteString("err = one.Struct(&result)\nreturn\n}\n")
+
+	// Mysql procedure returning result sets.
+	case isMysql:
+		for range inParams {
+			placeholders = append(placeholders, "?")
+		}
+		buffer.WriteString(fmt.Sprintf(
+			"func (dao *%s) %s(%s) (gdb.Result, error) {\n", daoName, methodName, methodArguments,
+		))
+		buffer.WriteString(fmt.Sprintf(
+			"return %s.GetAll(ctx, `CALL %s(%s)`%s)\n}\n",
+			dbCall, routine.Name, gstr.Join(placeholders, ", "), argumentValues,
+		))
+
+	// Pgsql procedure without any result.
+	default:
+		for range inParams {
+			placeholders = append(placeholders, "?")
+		}
+		buffer.WriteString(fmt.Sprintf(
+			"func (dao *%s) %s(%s) error {\n", daoName, methodName, methodArguments,
+		))
+		buffer.WriteString(fmt.Sprintf(
+			"_, err := %s.Exec(ctx, `CALL %s(%s)`%s)\nreturn err\n}\n",
+			dbCall, routine.Name, gstr.Join(placeholders, ", "), argumentValues,
+		))
+	}
+	return buffer.String()
+}
+
+// getRoutineGoType returns the golang type of routine parameter or return type `dbType`,
+// which is the same as the generated entity field.
+func getRoutineGoType(dbType string, in cGenDaoInternalInput) string {
+	field := &gdb.TableField{Name: "routine", Type: dbType}
+	return gstr.Trim(generateStructFieldDefinition(field, generateStructDefinitionInput{
+		cGenDaoInternalInput: in,
+	})[1], " #")
+}
+
+// getRoutineValueConversion returns the expression converting `*gvar.Var` variable `name` to `goType`.
+func getRoutineValueConversion(name, goType string) string {
+	switch goType {
+	case "int":
+		return name + ".Int()"
+	case "uint":
+		return name + ".Uint()"
+	case "int64":
+		return name + ".Int64()"
+	case "uint64":
+		return name + ".Uint64()"
+	case "float32":
+		return name + ".Float32()"
+	case "float64":
+		return name + ".Float64()"
+	case "bool":
+		return name + ".Bool()"
+	case "[]byte":
+		return name + ".Bytes()"
+	case "*gtime.Time":
+		return name + ".GTime()"
+	case "time.Time":
+		return name + ".Time()"
+	case "*gjson.Json":
+		return "gjson.New(" + name + ".String())"
+	default:
+		return name + ".String()"
+	}
+}
@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf-cli/v2/internal/consts"
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+const (
+	tplVarShardTables   = `{TplShardTables}`
+	tplVarShardComment  = `{TplShardComment}`
+	tplVarShardResolver = `{TplShardResolver}`
+	tplVarCtxMethods    = `{TplCtxMethods}`
+)
+
+// daoSharding is the sharding rule configuration of "gen dao".
+type daoSharding struct {
+	Pattern string `json:"pattern"` // Pattern of physical table names, the wildcard char '*' matches the shard suffix.
+	Name    string `json:"name"`    // Name of the logical table.
+}
+
+// daoShardTable is the logical table that is collapsed from sharded physical tables.
+type daoShardTable struct {
+	Name     string   // Name of the logical table.
+	Prefix   string   // Prefix of the physical table names before shard suffix.
+	Suffix   string   // Suffix of the physical table names after shard suffix.
+	Tables   []string // Physical table names sharing the same schema, the first one is the reference.
+	Suffixes []string // Shard suffixes of Tables.
+}
+
+// parseDaoShardings parses and returns the sharding rules from `sharding`,
+// which is either json array/object converted from the structured configuration,
+// or string like "order_*:order,log_*:log" from command line.
+func parseDaoShardings(sharding string) ([]daoSharding, error) {
+	var (
+		shardings []daoSharding
+		content   = gstr.Trim(sharding)
+	)
+	if content == "" {
+		return nil, nil
+	}
+	switch {
+	case gstr.HasPrefix(content, "["):
+		if err := gjson.DecodeTo(content, &shardings); err != nil {
+			return nil, gerror.Newf(`invalid sharding configuration: %v`, err)
+		}
+	case gstr.HasPrefix(content, "{"):
+		var item daoSharding
+		if err := gjson.DecodeTo(content, &item); err != nil {
+			return nil, gerror.Newf(`invalid sharding configuration: %v`, err)
+		}
+		shardings = append(shardings, item)
+	default:
+		for _, v := range gstr.SplitAndTrim(content, ",") {
+			array := gstr.SplitAndTrim(v, ":")
+			if len(array) != 2 {
+				return nil, gerror.Newf(`invalid sharding rule "%s", it should be like "order_*:order"`, v)
+			}
+			shardings = append(shardings, daoSharding{Pattern: array[0], Name: array[1]})
+		}
+	}
+	for _, v := range shardings {
+		if v.Name == "" || gstr.Count(v.Pattern, "*") != 1 {
+			return nil, gerror.Newf(
+				`invalid sharding rule "%s:%s", it requires logical table name and pattern with single wildcard char '*'`,
+				v.Pattern, v.Name,
+			)
+		}
+	}
+	return shardings, nil
+}
+
+// collapseShardedTables collapses the physical tables matching sharding rules to logical tables.
+// It returns the table names in which the sharded tables are replaced with logical table names,
+// and the logical tables mapping.
+// It fails if any shard diverges from the reference shard in columns, as the shard resolver
+// is computed from all the matched shards and a missing shard would route rows to wrong tables.
+func collapseShardedTables(
+	ctx context.Context, db gdb.DB, tableNames []string, shardings []daoSharding,
+) ([]string, map[string]*daoShardTable) {
+	var (
+		result      = make([]string, 0, len(tableNames))
+		shardTables = make(map[string]*daoShardTable)
+		matched     = make(map[string]*daoShardTable)
+	)
+	for _, sharding := range shardings {
+		var (
+			pos        = gstr.Pos(sharding.Pattern, "*")
+			shardTable = &daoShardTable{
+				Name:   sharding.Name,
+				Prefix: sharding.Pattern[:pos],
+				Suffix: sharding.Pattern[pos+1:],
+			}
+			expr = fmt.Sprintf(
+				`^%s(.+)%s$`, gregex.Quote(shardTable.Prefix), gregex.Quote(shardTable.Suffix),
+			)
+		)
+		for _, tableName := range tableNames {
+			if _, ok := matched[tableName]; ok {
+				continue
+			}
+			if match, _ := gregex.MatchString(expr, tableName); len(match) == 2 {
+				shardTable.Tables = append(shardTable.Tables, tableName)
+				shardTable.Suffixes = append(shardTable.Suffixes, match[1])
+				matched[tableName] = shardTable
+			}
+		}
+		if len(shardTable.Tables) == 0 {
+			mlog.Printf(`no table matches sharding pattern "%s"`, sharding.Pattern)
+			continue
+		}
+		sortDaoShardTable(shardTable)
+		verifyDaoShardTable(ctx, db, shardTable)
+		shardTables[shardTable.Name] = shardTable
+	}
+	for _, tableName := range tableNames {
+		shardTable, ok := matched[tableName]
+		if !ok {
+			// Normal table.
+			result = append(result, tableName)
+			continue
+		}
+		if tableName == shardTable.Tables[0] {
+			result = append(result, shardTable.Name)
+		}
+	}
+	return result, shardTables
+}
+
+// sortDaoShardTable sorts the shards of `shardTable` by suffix, numerically if all suffixes are numeric.
+func sortDaoShardTable(shardTable *daoShardTable) {
+	var (
+		numeric = isNumericShardSuffixes(shardTable.Suffixes)
+		indexes = make([]int, len(shardTable.Tables))
+	)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := shardTable.Suffixes[indexes[i]], shardTable.Suffixes[indexes[j]]
+		if numeric {
+			return gconv.Uint64(a) < gconv.Uint64(b)
+		}
+		return a < b
+	})
+	var (
+		tables   = make([]string, len(indexes))
+		suffixes = make([]string, len(indexes))
+	)
+	for i, index := range indexes {
+		tables[i] = shardTable.Tables[index]
+		suffixes[i] = shardTable.Suffixes[index]
+	}
+	shardTable.Tables, shardTable.Suffixes = tables, suffixes
+}
+
+// verifyDaoShardTable verifies that all shards share the schema of the first shard,
+// it fails with all the differences reported if any shard diverges.
+func verifyDaoShardTable(ctx context.Context, db gdb.DB, shardTable *daoShardTable) {
+	reference, err := db.TableFields(ctx, shardTable.Tables[0])
+	if err != nil {
+		mlog.Fatalf("fetching tables fields failed for table '%s':\n%v", shardTable.Tables[0], err)
+	}
+	divergences := make([]string, 0)
+	for i := 1; i < len(shardTable.Tables); i++ {
+		fieldMap, err := db.TableFields(ctx, shardTable.Tables[i])
+		if err != nil {
+			mlog.Fatalf("fetching tables fields failed for table '%s':\n%v", shardTable.Tables[i], err)
+		}
+		if differences := diffShardTableFields(reference, fieldMap); len(differences) > 0 {
+			divergences = append(divergences, fmt.Sprintf(
+				"shard table '%s' diverges from '%s':\n    %s",
+				shardTable.Tables[i], shardTable.Tables[0], gstr.Join(differences, "\n    "),
+			))
+		}
+	}
+	if len(divergences) > 0 {
+		mlog.Fatalf(
+			"shards of table '%s' must share the same columns, exclude the divergent shards "+
+				"from sharding pattern or fix their columns:\n%s",
+			shardTable.Name, gstr.Join(divergences, "\n"),
+		)
+	}
+}
+
+// diffShardTableFields compares the columns of shard `fieldMap` to `reference`,
+// and returns the descriptions of differences.
+func diffShardTableFields(reference, fieldMap map[string]*gdb.TableField) []string {
+	var differences []string
+	for _, name := range sortFieldKeyForDao(reference) {
+		var (
+			a     = reference[name]
+			b, ok = fieldMap[name]
+		)
+		if !ok {
+			differences = append(differences, fmt.Sprintf(`column "%s" is missing`, name))
+			continue
+		}
+		var (
+			aDefinition = fmt.Sprintf(`%d %s %t %s %v %s`, a.Index, a.Type, a.Null, a.Key, a.Default, a.Extra)
+			bDefinition = fmt.Sprintf(`%d %s %t %s %v %s`, b.Index, b.Type, b.Null, b.Key, b.Default, b.Extra)
+		)
+		if aDefinition != bDefinition {
+			differences = append(differences, fmt.Sprintf(
+				`column "%s" is "%s" but expected "%s"`, name, bDefinition, aDefinition,
+			))
+		}
+	}
+	for _, name := range sortFieldKeyForDao(fieldMap) {
+		if _, ok := reference[name]; !ok {
+			differences = append(differences, fmt.Sprintf(`column "%s" is unexpected`, name))
+		}
+	}
+	return differences
+}
+
+// isNumericShardSuffixes checks whether all the shard suffixes are numeric.
+func isNumericShardSuffixes(suffixes []string) bool {
+	for _, v := range suffixes {
+		if !gregex.IsMatchString(`^\d+$`, v) {
+			return false
+		}
+	}
+	return len(suffixes) > 0
+}
+
+// getDaoShardResolver returns the resolving function body, its description and its imports of `shardTable`.
+//
+// The resolving strategies are chosen by shard suffixes:
+// 1. Numeric suffixes from 0 to N-1, like "order_0" ... "order_63", the key is resolved by modulo N.
+// 2. Date suffixes in format of "Y", "Ym" or "Ymd", like "log_202401", the key is resolved as time.
+// 3. Other suffixes, the key is used as shard suffix directly.
+func getDaoShardResolver(shardTable *daoShardTable) (body, comment string, imports []string) {
+	var (
+		gdbImport = `"github.com/gogf/gf/v2/database/gdb"`
+		count     = len(shardTable.Suffixes)
+		numeric   = isNumericShardSuffixes(shardTable.Suffixes)
+		width     = len(shardTable.Suffixes[0])
+		padded    = false
+		modulo    = numeric
+	)
+	if numeric {
+		for i, v := range shardTable.Suffixes {
+			if gconv.Int(v) != i {
+				modulo = false
+				break
+			}
+			if len(v) != width {
+				width = 0
+			}
+			if len(v) > 1 && v[0] == '0' {
+				padded = true
+			}
+		}
+	}
+	if modulo {
+		format := "%d"
+		if padded && width > 0 {
+			format = fmt.Sprintf("%%0%dd", width)
+		}
+		body = fmt.Sprintf(
+			`return fmt.Sprintf("%s%s%s", gconv.Uint64(key)%%%d)`,
+			shardTable.Prefix, format, shardTable.Suffix, count,
+		)
+		comment = fmt.Sprintf(`The shard key is converted to uint64 and the shard is the key modulo %d.`, count)
+		return body, comment, []string{`"context"`, `"fmt"`, ``, gdbImport, `"github.com/gogf/gf/v2/util/gconv"`}
+	}
+	if numeric {
+		var layout string
+		switch width {
+		case 4:
+			layout = "Y"
+		case 6:
+			layout = "Ym"
+		case 8:
+			layout = "Ymd"
+		}
+		for _, v := range shardTable.Suffixes {
+			if layout == "" || len(v) != width {
+				layout = ""
+				break
+			}
+			if t, err := gtime.StrToTimeFormat(v, layout); err != nil || t.Format(layout) != v {
+				layout = ""
+				break
+			}
+		}
+		if layout != "" {
+			body = fmt.Sprintf(`return "%s" + gtime.New(key).Format("%s")`, shardTable.Prefix, layout)
+			if shardTable.Suffix != "" {
+				body += fmt.Sprintf(` + "%s"`, shardTable.Suffix)
+			}
+			comment = fmt.Sprintf(
+				`The shard key is converted to time, which can be time.Time, *gtime.Time, string or timestamp, `+
+					`and the shard is the time in format "%s".`, layout,
+			)
+			return body, comment, []string{`"context"`, ``, gdbImport, `"github.com/gogf/gf/v2/os/gtime"`}
+		}
+	}
+	body = fmt.Sprintf(`return "%s" + gconv.String(key)`, shardTable.Prefix)
+	if shardTable.Suffix != "" {
+		body += fmt.Sprintf(` + "%s"`, shardTable.Suffix)
+	}
+	comment = `The shard key is used as the shard suffix directly.`
+	return body, comment, []string{`"context"`, ``, gdbImport, `"github.com/gogf/gf/v2/util/gconv"`}
+}
+
+// generateDaoShard generates the shard resolving methods of DAO for sharded table.
+func generateDaoShard(
+	tableNameCamelCase, tableNameCamelLowerCase string,
+	dirPathDao, fileName string,
+	in cGenDaoInternalInput,
+) {
+	var (
+		path                   = gfile.Join(dirPathDao, "internal", fileName+"_shard.go")
+		body, comment, imports = getDaoShardResolver(in.Shard)
+		tables                 = make([]string, len(in.Shard.Tables))
+	)
+	for i, v := range in.Shard.Tables {
+		tables[i] = fmt.Sprintf(`"%s",`, v)
+	}
+	content := replaceDefaultVar(gstr.ReplaceByMap(consts.TemplateDaoDaoInternalShardContent, g.MapStrStr{
+		tplVarTableName:               in.TableName,
+		tplVarTableNameCamelCase:      tableNameCamelCase,
+		tplVarTableNameCamelLowerCase: tableNameCamelLowerCase,
+		tplVarPackageImports:          gstr.Join(imports, "\n"),
+		tplVarShardTables:             gstr.Join(tables, "\n"),
+		tplVarShardComment:            comment,
+		tplVarShardResolver:           body,
+		tplVarDbCall:                  getDaoDbCall(in),
+	}))
+	if err := putGeneratedContents(path, strings.TrimSpace(content), in.Force); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+	} else {
+		mlog.Print("generated:", path)
+	}
+}
+
+// getTableFieldsForDao retrieves and returns the fields of table `tableName`,
+// which retrieves the fields of the reference shard if `tableName` is a logical table of sharded tables.
+func getTableFieldsForDao(
+	ctx context.Context, db gdb.DB, tableName string, in cGenDaoInternalInput,
+) (map[string]*gdb.TableField, error) {
+	if shardTable, ok := in.ShardTables[tableName]; ok {
+		tableName = shardTable.Tables[0]
+	}
+	return db.TableFields(ctx, tableName)
+}
+
+// getDaoCtxMethods returns the Ctx and Transaction methods of DAO `daoNameCamelCase`,
+// in which the Ctx fails clearly for sharded table, as the logical table does not exist in database.
+func getDaoCtxMethods(daoNameCamelCase string, in cGenDaoInternalInput) string {
+	content := consts.TemplateDaoDaoInternalCtxContent
+	if in.Shard != nil {
+		content = consts.TemplateDaoDaoInternalShardCtxContent
+	}
+	return gstr.Trim(gstr.ReplaceByMap(content, g.MapStrStr{
+		tplVarTableName:          in.TableName,
+		tplVarTableNameCamelCase: daoNameCamelCase,
+		tplVarDbCall:             getDaoDbCall(in),
+	}))
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf-cli/v2/internal/consts"
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	tplVarTypedColumnDefine = `{TplTypedColumnDefine}`
+	tplVarTypedColumnName   = `{TplTypedColumnName}`
+	tplVarTypedColumnGoType = `{TplTypedColumnGoType}`
+	// typedColumnFileName is the file name of typed column descriptor definitions shared by all DAOs.
+	typedColumnFileName = `typed_column.go`
+)
+
+// typedColumnType defines a typed column descriptor type for a golang type.
+type typedColumnType struct {
+	Name    string // Descriptor type name.
+	GoType  string // Golang type of the column.
+	Eq      bool   // Whether it has Eq/In helpers.
+	Between bool   // Whether it has Between helper.
+	Like    bool   // Whether it has Like helper.
+}
+
+// typedColumnTypes are all the typed column descriptor types,
+// which covers all the golang types generated by "gen dao".
+// The column of type that is not in it uses the JsonColumn,
+// which has no condition helpers.
+var typedColumnTypes = []typedColumnType{
+	{Name: "StringColumn", GoType: "string", Eq: true, Between: true, Like: true},
+	{Name: "IntColumn", GoType: "int", Eq: true, Between: true},
+	{Name: "Int64Column", GoType: "int64", Eq: true, Between: true},
+	{Name: "UintColumn", GoType: "uint", Eq: true, Between: true},
+	{Name: "Uint64Column", GoType: "uint64", Eq: true, Between: true},
+	{Name: "Float32Column", GoType: "float32", Eq: true, Between: true},
+	{Name: "Float64Column", GoType: "float64", Eq: true, Between: true},
+	{Name: "BoolColumn", GoType: "bool", Eq: true},
+	{Name: "BytesColumn", GoType: "[]byte"},
+	{Name: "TimeColumn", GoType: "time.Time", Eq: true, Between: true},
+	{Name: "GTimeColumn", GoType: "*gtime.Time", Eq: true, Between: true},
+	{Name: "JsonColumn", GoType: "*gjson.Json"},
+}
+
+// getTypedColumnTypeName returns the typed column descriptor type name for golang type `goType`.
+func getTypedColumnTypeName(goType string) string {
+	for _, v := range typedColumnTypes {
+		if v.GoType == goType {
+			return v.Name
+		}
+	}
+	return "JsonColumn"
+}
+
+// generateDaoTypedColumnTypes generates the shared descriptor types of typed columns,
+// which are the same for all tables, so it is generated once for each generating.
+func generateDaoTypedColumnTypes(in cGenDaoInternalInput) {
+	typeBuffer := bytes.NewBuffer(nil)
+	for _, v := range typedColumnTypes {
+		content := consts.TemplateDaoTypedColumnTypeContent
+		if v.Eq {
+			content += consts.TemplateDaoTypedColumnEqContent
+		}
+		if v.Between {
+			content += consts.TemplateDaoTypedColumnBetweenContent
+		}
+		if v.Like {
+			content += consts.TemplateDaoTypedColumnLikeContent
+		}
+		typeBuffer.WriteString(gstr.ReplaceByMap(content, g.MapStrStr{
+			tplVarTypedColumnName:   v.Name,
+			tplVarTypedColumnGoType: v.GoType,
+		}))
+	}
+	typePath := gfile.Join(in.Path, defaultDaoPath, "internal", typedColumnFileName)
+	typeContent := replaceDefaultVar(gstr.ReplaceByMap(consts.TemplateDaoTypedColumnContent, g.MapStrStr{
+		tplVarTypedColumnDefine: gstr.Trim(typeBuffer.String()),
+	}))
+	if err := putGeneratedContents(typePath, strings.TrimSpace(typeContent), in.Force); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", typePath, err)
+	}
+}
+
+// generateDaoTypedColumn generates the typed column descriptors of table,
+// which use the shared descriptor types generated by generateDaoTypedColumnTypes.
+func generateDaoTypedColumn(
+	tableNameCamelCase, tableNameCamelLowerCase string,
+	dirPathDao, fileName string,
+	fieldMap map[string]*gdb.TableField,
+	in cGenDaoInternalInput,
+) {
+	// Table typed column descriptors.
+	var (
+		names       = sortFieldKeyForDao(fieldMap)
+		defineArray = make([][]string, len(names))
+		valueArray  = make([][]string, len(names))
+		structInput = generateStructDefinitionInput{cGenDaoInternalInput: in}
+	)
+	for index, name := range names {
+		var (
+			field    = fieldMap[name]
+			goType   = gstr.Trim(generateStructFieldDefinition(field, structInput)[1], " #")
+			typeName = getTypedColumnTypeName(goType)
+		)
+		defineArray[index] = []string{
+			"    #" + gstr.CaseCamel(field.Name),
+			" # " + typeName,
+			" #" + fmt.Sprintf(`// %s`, formatComment(field.Comment)),
+		}
+		valueArray[index] = []string{
+			"            #" + gstr.CaseCamel(field.Name) + ":",
+			fmt.Sprintf(
+				` #%s{TypedColumn{Name: %q, GoType: %q, DbType: %q, Nullable: %t}},`,
+				typeName, field.Name, goType, field.Type, field.Null,
+			),
+		}
+	}
+	path := gfile.Join(dirPathDao, "internal", fileName+"_typed.go")
+	content := replaceDefaultVar(gstr.ReplaceByMap(consts.TemplateDaoDaoInternalTypedContent, g.MapStrStr{
+		tplVarTableName:               in.TableName,
+		tplVarTableNameCamelCase:      tableNameCamelCase,
+		tplVarTableNameCamelLowerCase: tableNameCamelLowerCase,
+		tplVarColumnDefine:            gstr.Trim(renderTypedColumnTable(defineArray)),
+		tplVarColumnNames:             gstr.Trim(renderTypedColumnTable(valueArray)),
+	}))
+	if err := putGeneratedContents(path, strings.TrimSpace(content), in.Force); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+	} else {
+		mlog.Print("generated:", path)
+	}
+}
+
+// renderTypedColumnTable renders the aligned content of `array` like generateColumnDefinitionForDao.
+func renderTypedColumnTable(array [][]string) string {
+	buffer := bytes.NewBuffer(nil)
+	tw := tablewriter.NewWriter(buffer)
+	tw.SetBorder(false)
+	tw.SetRowLine(false)
+	tw.SetAutoWrapText(false)
+	tw.SetColumnSeparator("")
+	tw.AppendBulk(array)
+	tw.Render()
+	// Let's do this hack of table writer for indent!
+	return gstr.Replace(buffer.String(), "  #", "")
+}
@@ -0,0 +1,647 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gtag"
+)
+
+const (
+	cGenDdlConfig = `gfcli.gen.ddl`
+	cGenDdlBrief  = `parse entity structs and generate "CREATE TABLE" statements`
+	cGenDdlEg     = `
+gf gen ddl
+gf gen ddl -p ./internal/model/entity -d pgsql
+gf gen ddl -t user,user_detail -o manifest/sql/schema.sql
+gf gen ddl -d sqlite -f gf_
+`
+	cGenDdlAd = `
+CONFIGURATION SUPPORT
+    Options are also supported by configuration file.
+    The configuration node name is "gfcli.gen.ddl", for example(config.yaml):
+    gfcli:
+      gen:
+        ddl:
+          path:    "internal/model/entity"
+          output:  "manifest/sql/schema.sql"
+          dialect: "pgsql"
+
+STRUCT DEFINITION
+    Each struct in the entity files is generated as a table, and each exported field is generated as a column.
+    The table name is retrieved in order of:
+    1. the "orm" tag of the "g.Meta" field, like: orm:"table:user";
+    2. the struct comment generated by "gf gen dao", like: "User is the golang structure for table user.";
+    3. the snake case of the struct name.
+    The column name is retrieved in order of:
+    1. the first item of the "orm" tag, like: orm:"id,primary";
+    2. the "json" tag if it is in lower case;
+    3. the snake case of the field name.
+    The column is marked as primary key if its "orm" tag contains "primary", or else the field "Id" is used.
+    The column is nullable if its type is a pointer, unless it has "required" rule in "v" tag.
+    The length of string column is retrieved from "length" or "max-length" rule of the "v" tag.
+    The column comment is retrieved from "description" tag or the field comment.
+    Fields with tag orm:"-" are ignored.
+`
+	cGenDdlBriefPath    = `directory path of the entity go files, default is "internal/model/entity"`
+	cGenDdlBriefOutput  = `output sql file path, it prints the statements to stdout if it's empty`
+	cGenDdlBriefDialect = `database dialect of the generated statements, which can be: mysql, pgsql, sqlite. default is "mysql"`
+	cGenDdlBriefTables  = `generate statements only for given tables, multiple table names separated with ','`
+	cGenDdlBriefPrefix  = `add specified prefix for all table names`
+)
+
+const (
+	ddlDefaultStringLength = 255
+)
+
+func init() {
+	gtag.Sets(g.MapStrStr{
+		`cGenDdlConfig`:       cGenDdlConfig,
+		`cGenDdlBrief`:        cGenDdlBrief,
+		`cGenDdlEg`:           cGenDdlEg,
+		`cGenDdlAd`:           cGenDdlAd,
+		`cGenDdlBriefPath`:    cGenDdlBriefPath,
+		`cGenDdlBriefOutput`:  cGenDdlBriefOutput,
+		`cGenDdlBriefDialect`: cGenDdlBriefDialect,
+		`cGenDdlBriefTables`:  cGenDdlBriefTables,
+		`cGenDdlBriefPrefix`:  cGenDdlBriefPrefix,
+	})
+}
+
+type (
+	cGenDdlInput struct {
+		g.Meta  `name:"ddl" config:"{cGenDdlConfig}" brief:"{cGenDdlBrief}" eg:"{cGenDdlEg}" ad:"{cGenDdlAd}"`
+		Path    string `name:"path"    short:"p" brief:"{cGenDdlBriefPath}" d:"internal/model/entity"`
+		Output  string `name:"output"  short:"o" brief:"{cGenDdlBriefOutput}"`
+		Dialect string `name:"dialect" short:"d" brief:"{cGenDdlBriefDialect}" d:"mysql"`
+		Tables  string `name:"tables"  short:"t" brief:"{cGenDdlBriefTables}"`
+		Prefix  string `name:"prefix"  short:"f" brief:"{cGenDdlBriefPrefix}"`
+	}
+	cGenDdlOutput struct{}
+
+	// ddlTable is the table definition parsed from entity struct.
+	ddlTable struct {
+		Name    string
+		Comment string
+		Columns []ddlColumn
+	}
+
+	// ddlColumn is the column definition parsed from entity struct field.
+	ddlColumn struct {
+		Name          string
+		Type          string
+		Null          bool
+		Primary       bool
+		AutoIncrement bool
+		Comment       string
+	}
+
+	// ddlStructParser parses entity structs from go files into table definitions.
+	ddlStructParser struct {
+		dialect  string
+		structs  map[string]*ast.TypeSpec // Parsed structs, used for embedded struct fields.
+		embedded map[string]bool          // Names of structs that are embedded by other structs.
+	}
+)
+
+func (c cGen) Ddl(ctx context.Context, in cGenDdlInput) (out *cGenDdlOutput, err error) {
+	in.Dialect = gstr.ToLower(in.Dialect)
+	switch in.Dialect {
+	case "mysql", "pgsql", "sqlite":
+	default:
+		mlog.Fatalf(`unsupported dialect "%s", it should be one of: mysql, pgsql, sqlite`, in.Dialect)
+	}
+	if !gfile.Exists(in.Path) {
+		mlog.Fatalf(`entity path "%s" does not exist`, in.Path)
+	}
+	files, err := gfile.ScanDirFile(in.Path, "*.go", false)
+	if err != nil {
+		mlog.Fatal(err)
+	}
+	var (
+		fileSet = token.NewFileSet()
+		parsed  = make([]*ast.File, 0)
+		p       = &ddlStructParser{
+			dialect:  in.Dialect,
+			structs:  make(map[string]*ast.TypeSpec),
+			embedded: make(map[string]bool),
+		}
+	)
+	for _, file := range files {
+		if gstr.HasSuffix(file, "_test.go") {
+			continue
+		}
+		astFile, err := parser.ParseFile(fileSet, file, nil, parser.ParseComments)
+		if err != nil {
+			mlog.Fatalf(`parsing go file "%s" failed: %+v`, file, err)
+		}
+		parsed = append(parsed, astFile)
+		for _, spec := range p.structSpecs(astFile) {
+			p.structs[spec.Name.Name] = spec
+		}
+	}
+
+	var (
+		tables     = make([]*ddlTable, 0)
+		tableNames = gstr.SplitAndTrim(in.Tables, ",")
+	)
+	for _, astFile := range parsed {
+		for _, decl := range astFile.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if _, ok = typeSpec.Type.(*ast.StructType); !ok || !typeSpec.Name.IsExported() {
+					continue
+				}
+				// Embedded structs are parts of other tables.
+				if p.embedded[typeSpec.Name.Name] {
+					continue
+				}
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				table := p.parseTable(typeSpec, doc)
+				if len(tableNames) > 0 && !gstr.InArray(tableNames, table.Name) {
+					continue
+				}
+				table.Name = in.Prefix + table.Name
+				if len(table.Columns) == 0 {
+					mlog.Printf(`struct "%s" has no column definition, ignored`, typeSpec.Name.Name)
+					continue
+				}
+				tables = append(tables, table)
+			}
+		}
+	}
+	if len(tables) == 0 {
+		mlog.Fatalf(`no entity struct found in "%s"`, in.Path)
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	buffer.WriteString(fmt.Sprintf(
+		"-- Generated by \"gf gen ddl\" from \"%s\" at %s\n",
+		in.Path, gtime.Now().String(),
+	))
+	for _, table := range tables {
+		buffer.WriteString("\n")
+		buffer.WriteString(generateDdlForTable(table, in.Dialect))
+	}
+	if in.Output == "" {
+		fmt.Print(buffer.String())
+		return
+	}
+	if err = gfile.PutContents(in.Output, buffer.String()); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", in.Output, err)
+	}
+	mlog.Print("generated:", in.Output)
+	mlog.Print("done!")
+	return
+}
+
+// structSpecs returns all the struct type specs of given file, and records the embedded struct names.
+func (p *ddlStructParser) structSpecs(file *ast.File) []*ast.TypeSpec {
+	specs := make([]*ast.TypeSpec, 0)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if typeSpec, ok := node.(*ast.TypeSpec); ok {
+			if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+				specs = append(specs, typeSpec)
+				for _, field := range structType.Fields.List {
+					if len(field.Names) == 0 {
+						p.embedded[gstr.TrimLeftStr(ddlTypeString(field.Type), "*")] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+	return specs
+}
+
+// parseTable parses the struct as table definition.
+func (p *ddlStructParser) parseTable(spec *ast.TypeSpec, doc *ast.CommentGroup) *ddlTable {
+	table := &ddlTable{
+		Name: gstr.CaseSnake(spec.Name.Name),
+	}
+	if doc != nil {
+		comment := normalizeComment(doc.Text())
+		// The struct comment generated by "gf gen dao".
+		if match, _ := gregex.MatchString(`is the golang structure for table\s+([\w\.]+)`, comment); len(match) > 1 {
+			table.Name = gstr.TrimRight(match[1], ".")
+		} else {
+			table.Comment = gstr.TrimLeftStr(comment, spec.Name.Name+" ")
+		}
+	}
+	table.Columns = p.parseColumns(spec.Type.(*ast.StructType), table, map[string]bool{
+		spec.Name.Name: true,
+	})
+	// Uses "Id" field as primary key if no primary key specified.
+	hasPrimary := false
+	for _, column := range table.Columns {
+		hasPrimary = hasPrimary || column.Primary
+	}
+	if !hasPrimary {
+		for i, column := range table.Columns {
+			if column.Name == "id" {
+				table.Columns[i].Primary = true
+				table.Columns[i].Null = false
+				break
+			}
+		}
+	}
+	// Only single integer primary key is auto increment.
+	primaryCount := 0
+	for _, column := range table.Columns {
+		if column.Primary {
+			primaryCount++
+		}
+	}
+	for i, column := range table.Columns {
+		if !column.Primary || primaryCount != 1 {
+			table.Columns[i].AutoIncrement = false
+		}
+	}
+	return table
+}
+
+// parseColumns parses the fields of struct as columns.
+// The `visited` is used for avoiding recursive embedded structs.
+func (p *ddlStructParser) parseColumns(structType *ast.StructType, table *ddlTable, visited map[string]bool) []ddlColumn {
+	columns := make([]ddlColumn, 0)
+	for _, field := range structType.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if v, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(v)
+			}
+		}
+		var (
+			ormTag   = tag.Get("orm")
+			ormItems = gstr.SplitAndTrim(ormTag, ",")
+		)
+		if ormTag == "-" {
+			continue
+		}
+		// Embedded field.
+		if len(field.Names) == 0 {
+			typeName := ddlTypeString(field.Type)
+			if typeName == "g.Meta" || typeName == "gmeta.Meta" {
+				for _, item := range ormItems {
+					if gstr.HasPrefix(item, "table:") {
+						table.Name = gstr.Trim(gstr.TrimLeftStr(item, "table:"))
+					}
+				}
+				continue
+			}
+			typeName = gstr.TrimLeftStr(typeName, "*")
+			if spec, ok := p.structs[typeName]; ok && !visited[typeName] {
+				visited[typeName] = true
+				columns = append(columns, p.parseColumns(spec.Type.(*ast.StructType), table, visited)...)
+				delete(visited, typeName)
+			} else {
+				mlog.Printf(`embedded field "%s" of table "%s" cannot be resolved, ignored`, typeName, table.Name)
+			}
+			continue
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			column := p.parseColumn(name.Name, field, tag, ormItems)
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// parseColumn parses the struct field as column definition.
+func (p *ddlStructParser) parseColumn(fieldName string, field *ast.Field, tag reflect.StructTag, ormItems []string) ddlColumn {
+	var (
+		column = ddlColumn{
+			Name: gstr.CaseSnake(fieldName),
+		}
+		jsonName = gstr.Trim(gstr.Split(tag.Get("json"), ",")[0])
+		rules    = gstr.SplitAndTrim(tag.Get("v"), "|")
+		length   = 0
+	)
+	if len(ormItems) > 0 && ormItems[0] != "" && !gstr.Contains(ormItems[0], ":") && ormItems[0] != "primary" {
+		column.Name = ormItems[0]
+	} else if jsonName != "" && jsonName != "-" && gstr.ToLower(jsonName) == jsonName {
+		column.Name = jsonName
+	}
+	for _, item := range ormItems {
+		if item == "primary" {
+			column.Primary = true
+		}
+	}
+	// Validation rules like: required|length:1,32#message.
+	for _, rule := range rules {
+		rule = gstr.Split(rule, "#")[0]
+		var (
+			array = gstr.SplitAndTrim(rule, ":")
+			name  = ""
+		)
+		if len(array) > 0 {
+			name = array[0]
+		}
+		switch name {
+		case "length", "max-length", "size":
+			if len(array) > 1 {
+				values := gstr.SplitAndTrim(array[1], ",")
+				length, _ = strconv.Atoi(values[len(values)-1])
+			}
+		}
+	}
+	_, isPointer := field.Type.(*ast.StarExpr)
+	column.Null = isPointer && !gstr.InArray(ddlRuleNames(rules), "required") && !column.Primary
+	column.Type, column.AutoIncrement = p.columnType(field.Type, length)
+
+	// Column comment from "description" tag or field comment.
+	if description := tag.Get("description"); description != "" {
+		column.Comment = description
+	} else if field.Comment != nil {
+		column.Comment = normalizeComment(field.Comment.Text())
+	} else if field.Doc != nil {
+		column.Comment = normalizeComment(field.Doc.Text())
+	}
+	return column
+}
+
+// columnType converts the go type to column type of current dialect.
+// It also returns whether the type can be auto incremented.
+func (p *ddlStructParser) columnType(expr ast.Expr, length int) (columnType string, isInteger bool) {
+	var (
+		typeName   = gstr.TrimLeftStr(ddlTypeString(expr), "*")
+		isMysql    = p.dialect == "mysql"
+		isPgsql    = p.dialect == "pgsql"
+		isUnsigned = gstr.HasPrefix(typeName, "uint")
+	)
+	switch typeName {
+	case "int", "int32", "uint", "uint32", "int8", "uint8", "int16", "uint16", "rune":
+		isInteger = true
+		switch {
+		case isMysql:
+			columnType = "int"
+			if typeName == "int8" || typeName == "uint8" {
+				columnType = "tinyint"
+			} else if typeName == "int16" || typeName == "uint16" {
+				columnType = "smallint"
+			}
+			if isUnsigned {
+				columnType += " unsigned"
+			}
+		case isPgsql:
+			if typeName == "int8" || typeName == "int16" || typeName == "uint8" {
+				columnType = "smallint"
+			} else if isUnsigned {
+				// PostgreSQL has no unsigned integer, uses larger one for avoiding overflow.
+				columnType = "bigint"
+			} else {
+				columnType = "integer"
+			}
+		default:
+			columnType = "INTEGER"
+		}
+
+	case "int64", "uint64":
+		isInteger = true
+		switch {
+		case isMysql:
+			columnType = "bigint"
+			if isUnsigned {
+				columnType += " unsigned"
+			}
+		case isPgsql:
+			columnType = "bigint"
+		default:
+			columnType = "INTEGER"
+		}
+
+	case "float32":
+		switch {
+		case isMysql:
+			columnType = "float"
+		case isPgsql:
+			columnType = "real"
+		default:
+			columnType = "REAL"
+		}
+
+	case "float64":
+		switch {
+		case isMysql:
+			columnType = "double"
+		case isPgsql:
+			columnType = "double precision"
+		default:
+			columnType = "REAL"
+		}
+
+	case "bool":
+		switch {
+		case isMysql:
+			columnType = "tinyint(1)"
+		case isPgsql:
+			columnType = "boolean"
+		default:
+			columnType = "INTEGER"
+		}
+
+	case "string":
+		switch {
+		case isMysql, isPgsql:
+			if length <= 0 {
+				length = ddlDefaultStringLength
+			}
+			columnType = fmt.Sprintf("varchar(%d)", length)
+		default:
+			columnType = "TEXT"
+		}
+
+	case "[]byte", "[]uint8":
+		switch {
+		case isMysql:
+			columnType = "blob"
+		case isPgsql:
+			columnType = "bytea"
+		default:
+			columnType = "BLOB"
+		}
+
+	case "time.Time", "gtime.Time":
+		switch {
+		case isMysql:
+			columnType = "datetime"
+		case isPgsql:
+			columnType = "timestamp"
+		default:
+			columnType = "DATETIME"
+		}
+
+	case "gjson.Json", "json.RawMessage":
+		switch {
+		case isMysql:
+			columnType = "json"
+		case isPgsql:
+			columnType = "jsonb"
+		default:
+			columnType = "TEXT"
+		}
+
+	default:
+		// Slices, maps and other structs are stored as json text.
+		if isMysql || isPgsql {
+			columnType = "text"
+		} else {
+			columnType = "TEXT"
+		}
+	}
+	return
+}
+
+// generateDdlForTable generates and returns the "CREATE TABLE" statement of table in given dialect.
+func generateDdlForTable(table *ddlTable, dialect string) string {
+	var (
+		buffer      = bytes.NewBuffer(nil)
+		definitions = make([]string, 0)
+		comments    = make([]string, 0)
+		primaryKeys = make([]string, 0)
+		quote       = func(name string) string {
+			if dialect == "mysql" {
+				return "`" + name + "`"
+			}
+			return `"` + name + `"`
+		}
+		quoteValue = func(value string) string {
+			return "'" + gstr.Replace(value, "'", "''") + "'"
+		}
+		inlinePrimary = false
+	)
+	for _, column := range table.Columns {
+		if column.Primary {
+			primaryKeys = append(primaryKeys, quote(column.Name))
+		}
+	}
+	for _, column := range table.Columns {
+		definition := quote(column.Name) + " "
+		switch {
+		case column.AutoIncrement && dialect == "pgsql":
+			if gstr.Contains(column.Type, "bigint") {
+				definition += "bigserial"
+			} else {
+				definition += "serial"
+			}
+		case column.AutoIncrement && dialect == "sqlite":
+			// SQLite only supports auto increment for inline primary key.
+			definition += "INTEGER PRIMARY KEY AUTOINCREMENT"
+			inlinePrimary = true
+		default:
+			definition += column.Type
+		}
+		if !inlinePrimary || !column.AutoIncrement {
+			if column.Null {
+				definition += " NULL"
+			} else {
+				definition += " NOT NULL"
+			}
+		}
+		if column.AutoIncrement && dialect == "mysql" {
+			definition += " AUTO_INCREMENT"
+		}
+		if column.Comment != "" {
+			switch dialect {
+			case "mysql":
+				definition += " COMMENT " + quoteValue(column.Comment)
+			case "pgsql":
+				comments = append(comments, fmt.Sprintf(
+					"COMMENT ON COLUMN %s.%s IS %s;",
+					quote(table.Name), quote(column.Name), quoteValue(column.Comment),
+				))
+			default:
+				// SQLite does not support column comment, it uses sql comment instead.
+				definition += " -- " + column.Comment
+			}
+		}
+		definitions = append(definitions, definition)
+	}
+	if len(primaryKeys) > 0 && !inlinePrimary {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", gstr.Join(primaryKeys, ", ")))
+	}
+
+	buffer.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", quote(table.Name)))
+	for i, definition := range definitions {
+		// The comma should be placed before the trailing sql comment.
+		var (
+			sqlComment = ""
+			pos        = gstr.Pos(definition, " -- ")
+		)
+		if dialect == "sqlite" && pos != -1 {
+			definition, sqlComment = definition[:pos], definition[pos:]
+		}
+		buffer.WriteString("    " + definition)
+		if i < len(definitions)-1 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString(sqlComment + "\n")
+	}
+	buffer.WriteString(")")
+	if dialect == "mysql" {
+		buffer.WriteString(" ENGINE=InnoDB DEFAULT CHARSET=utf8mb4")
+		if table.Comment != "" {
+			buffer.WriteString(" COMMENT=" + quoteValue(table.Comment))
+		}
+	}
+	buffer.WriteString(";\n")
+	if dialect == "pgsql" && table.Comment != "" {
+		buffer.WriteString(fmt.Sprintf(
+			"COMMENT ON TABLE %s IS %s;\n", quote(table.Name), quoteValue(table.Comment),
+		))
+	}
+	for _, comment := range comments {
+		buffer.WriteString(comment + "\n")
+	}
+	return buffer.String()
+}
+
+// ddlTypeString returns the type string of given type expression, like: *gtime.Time, []byte.
+func ddlTypeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + ddlTypeString(t.X)
+	case *ast.SelectorExpr:
+		return ddlTypeString(t.X) + "." + t.Sel.Name
+	case *ast.ArrayType:
+		return "[]" + ddlTypeString(t.Elt)
+	case *ast.MapType:
+		return "map[" + ddlTypeString(t.Key) + "]" + ddlTypeString(t.Value)
+	case *ast.InterfaceType:
+		return "interface{}"
+	}
+	return ""
+}
+
+// ddlRuleNames returns the rule names of given validation rules.
+func ddlRuleNames(rules []string) []string {
+	names := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		names = append(names, gstr.Trim(gstr.Split(gstr.Split(rule, "#")[0], ":")[0]))
+	}
+	return names
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+// fieldDirectivePattern matches the generating directives in column comment, like:
+// "@type:github.com/x/money.Amount", "@json:-", "@omitempty", "@enum:active,disabled", "@deprecated".
+// The directive should be at the beginning of comment or after white space,
+// so that the email address in comment is not considered as directive,
+// and the directive name should be a whole word, so that "@typescript" is not considered as "@type".
+const fieldDirectivePattern = `(^|\s)@(type|json|omitempty|enum|deprecated)\b(:(\S*))?`
+
+// fieldDirectives is the generating directives of a table field, which are parsed from column comment.
+type fieldDirectives struct {
+	Type       string   // Type is the golang type of the field, which can be with import path.
+	JsonIgnore bool     // JsonIgnore specifies the field is ignored in json, by "@json:-".
+	JsonName   string   // JsonName is the custom json name of the field, by "@json:name".
+	OmitEmpty  bool     // OmitEmpty adds "omitempty" option to json tag.
+	Enum       []string // Enum is the allowed values of the field.
+	Deprecated bool     // Deprecated marks the field deprecated.
+}
+
+// parseFieldDirectives parses and returns the generating directives from column comment.
+func parseFieldDirectives(comment string) fieldDirectives {
+	var (
+		directives fieldDirectives
+		matches, _ = gregex.MatchAllString(fieldDirectivePattern, comment)
+	)
+	for _, match := range matches {
+		value := match[4]
+		switch match[2] {
+		case "type":
+			directives.Type = value
+		case "json":
+			if value == "-" {
+				directives.JsonIgnore = true
+			} else {
+				directives.JsonName = value
+			}
+		case "omitempty":
+			directives.OmitEmpty = true
+		case "enum":
+			directives.Enum = gstr.SplitAndTrim(value, ",")
+		case "deprecated":
+			directives.Deprecated = true
+		}
+	}
+	return directives
+}
+
+// removeFieldDirectives removes the generating directives from column comment.
+func removeFieldDirectives(comment string) string {
+	comment, _ = gregex.ReplaceString(fieldDirectivePattern, "$1", comment)
+	comment, _ = gregex.ReplaceString(`\s{2,}`, " ", comment)
+	return gstr.Trim(comment)
+}
+
+// JsonTag returns the json tag value of field name `name` applied with the directives.
+func (d fieldDirectives) JsonTag(name string) string {
+	if d.JsonIgnore {
+		return "-"
+	}
+	if d.JsonName != "" {
+		name = d.JsonName
+	}
+	if d.OmitEmpty {
+		name += ",omitempty"
+	}
+	return name
+}
+
+// GoType returns the golang type name and its import path of directive "@type",
+// like: "*github.com/x/money.Amount" returns "*money.Amount" and "github.com/x/money",
+// and "github.com/gofrs/uuid/v5.UUID" returns "uuid.UUID" and "github.com/gofrs/uuid/v5".
+// The import path is empty if the type is builtin or it has no import path.
+func (d fieldDirectives) GoType() (typeName, importPath string) {
+	var (
+		prefix = ""
+		name   = d.Type
+	)
+	if match, _ := gregex.MatchString(`^([\*\[\]]*)(.+)$`, name); len(match) == 3 {
+		prefix, name = match[1], match[2]
+	}
+	var (
+		slashPos = gstr.PosR(name, "/")
+		dotPos   = gstr.PosR(name, ".")
+	)
+	if slashPos == -1 || dotPos < slashPos {
+		return d.Type, ""
+	}
+	importPath = name[:dotPos]
+	return prefix + getImportPackageName(importPath) + name[dotPos:], importPath
+}
+
+// getImportPackageName returns the package name of import path `importPath`, which is used as the qualifier,
+// it is the last element of path without major version like "/v5" or ".v3" and prefix "go-".
+// The import is aliased with the name if it is not the last element of path, see getImportPartContent.
+func getImportPackageName(importPath string) string {
+	var (
+		array = gstr.Split(importPath, "/")
+		name  = array[len(array)-1]
+	)
+	if len(array) > 1 && gregex.IsMatchString(`^v\d+$`, name) {
+		name = array[len(array)-2]
+	}
+	name, _ = gregex.ReplaceString(`\.v\d+$`, "", name)
+	name = gstr.TrimLeftStr(name, "go-")
+	name, _ = gregex.ReplaceString(`[^\w]`, "", name)
+	return name
+}
+
+// getFieldDirectiveImports returns the import paths of custom types specified by "@type" directives of fields.
+func getFieldDirectiveImports(fieldMap map[string]*gdb.TableField) []string {
+	var imports = make([]string, 0)
+	for _, name := range sortFieldKeyForDao(fieldMap) {
+		if _, importPath := parseFieldDirectives(fieldMap[name].Comment).GoType(); importPath != "" {
+			imports = append(imports, importPath)
+		}
+	}
+	return imports
+}
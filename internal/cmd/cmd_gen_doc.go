@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+
+	"github.com/gogf/gf-cli/v2/internal/consts"
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gtag"
+)
+
+const (
+	cGenDocConfig = `gfcli.gen.doc`
+	cGenDocBrief  = `generate data dictionary documentation of database tables in markdown and html`
+	cGenDocEg     = `
+gf gen doc
+gf gen doc -l "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+gf gen doc -p ./manifest/docs -t "user*,order*" -f html
+gf gen doc -r gf_ -j Snake
+`
+	cGenDocAd = `
+CONFIGURATION SUPPORT
+    Options are also supported by configuration file.
+    The configuration node name is "gfcli.gen.doc", for example(config.yaml):
+    gfcli:
+      gen:
+        doc:
+          link:         "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+          path:         "manifest/docs"
+          tablesEx:     "*_log"
+          removePrefix: "gf_"
+          jsonCase:     "CamelLower"
+    It's suggested using the same "prefix", "removePrefix", "jsonCase", "stdTime" and "gJsonSupport" options
+    as "gf gen dao", so that the entity names, field names and json tags in documentation match the generated codes.
+`
+	cGenDocBriefPath         = `directory path for generated documentation files`
+	cGenDocBriefName         = `file name of generated documentation files without extension`
+	cGenDocBriefTitle        = `title of the documentation`
+	cGenDocBriefFormat       = `documentation format, which can be: markdown, html, all. default is "all"`
+	cGenDocBriefLink         = `database configuration, the same as the ORM configuration of GoFrame`
+	cGenDocBriefTables       = `only the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cGenDocBriefTablesEx     = `excluding the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cGenDocBriefPrefix       = `add prefix for all table names, the same as "gf gen dao"`
+	cGenDocBriefRemovePrefix = `remove specified prefix of the table, multiple prefix separated with ',', the same as "gf gen dao"`
+	cGenDocBriefJsonCase     = `json tag case for entity fields, the same as "gf gen dao", default is "CamelLower"`
+	cGenDocBriefStdTime      = `use time.Time from stdlib instead of gtime.Time for time fields, the same as "gf gen dao"`
+	cGenDocBriefGJsonSupport = `use *gjson.Json instead of string for json fields, the same as "gf gen dao"`
+	cGenDocBriefGroup        = `
+specifying the configuration group name of database,
+it's not necessary and the default value is "default"
+`
+)
+
+func init() {
+	gtag.Sets(g.MapStrStr{
+		`cGenDocConfig`:            cGenDocConfig,
+		`cGenDocBrief`:             cGenDocBrief,
+		`cGenDocEg`:                cGenDocEg,
+		`cGenDocAd`:                cGenDocAd,
+		`cGenDocBriefPath`:         cGenDocBriefPath,
+		`cGenDocBriefName`:         cGenDocBriefName,
+		`cGenDocBriefTitle`:        cGenDocBriefTitle,
+		`cGenDocBriefFormat`:       cGenDocBriefFormat,
+		`cGenDocBriefLink`:         cGenDocBriefLink,
+		`cGenDocBriefTables`:       cGenDocBriefTables,
+		`cGenDocBriefTablesEx`:     cGenDocBriefTablesEx,
+		`cGenDocBriefPrefix`:       cGenDocBriefPrefix,
+		`cGenDocBriefRemovePrefix`: cGenDocBriefRemovePrefix,
+		`cGenDocBriefJsonCase`:     cGenDocBriefJsonCase,
+		`cGenDocBriefStdTime`:      cGenDocBriefStdTime,
+		`cGenDocBriefGJsonSupport`: cGenDocBriefGJsonSupport,
+		`cGenDocBriefGroup`:        cGenDocBriefGroup,
+	})
+}
+
+type (
+	cGenDocInput struct {
+		g.Meta       `name:"doc" config:"{cGenDocConfig}" brief:"{cGenDocBrief}" eg:"{cGenDocEg}" ad:"{cGenDocAd}"`
+		Path         string `name:"path"         short:"p" brief:"{cGenDocBriefPath}" d:"manifest/docs"`
+		Name         string `name:"name"         short:"a" brief:"{cGenDocBriefName}" d:"data_dictionary"`
+		Title        string `name:"title"        short:"i" brief:"{cGenDocBriefTitle}" d:"Data Dictionary"`
+		Format       string `name:"format"       short:"f" brief:"{cGenDocBriefFormat}" d:"all"`
+		Link         string `name:"link"         short:"l" brief:"{cGenDocBriefLink}"`
+		Group        string `name:"group"        short:"g" brief:"{cGenDocBriefGroup}" d:"default"`
+		Tables       string `name:"tables"       short:"t" brief:"{cGenDocBriefTables}"`
+		TablesEx     string `name:"tablesEx"     short:"e" brief:"{cGenDocBriefTablesEx}"`
+		Prefix       string `name:"prefix"       short:"x" brief:"{cGenDocBriefPrefix}"`
+		RemovePrefix string `name:"removePrefix" short:"r" brief:"{cGenDocBriefRemovePrefix}"`
+		JsonCase     string `name:"jsonCase"     short:"j" brief:"{cGenDocBriefJsonCase}" d:"CamelLower"`
+		StdTime      bool   `name:"stdTime"      short:"s" brief:"{cGenDocBriefStdTime}"      orphan:"true"`
+		GJsonSupport bool   `name:"gJsonSupport" short:"n" brief:"{cGenDocBriefGJsonSupport}" orphan:"true"`
+	}
+	cGenDocOutput struct{}
+
+	// docData is the data for rendering documentation.
+	docData struct {
+		Title     string
+		CreatedAt string
+		Tables    []*docTable
+	}
+	// docTable is the documentation of a table.
+	docTable struct {
+		Name        string
+		Entity      string // Entity struct name generated by "gen dao".
+		Comment     string
+		Columns     []*docColumn
+		Indexes     []*docIndex
+		ForeignKeys []*docForeignKey
+	}
+	// docColumn is the documentation of a table column.
+	docColumn struct {
+		Index   int
+		Name    string
+		Type    string
+		Null    string
+		Default string
+		Key     string
+		Extra   string
+		Comment string
+		Field   string // Entity field name generated by "gen dao".
+		GoType  string // Entity field type generated by "gen dao".
+		Json    string // Entity field json tag generated by "gen dao".
+	}
+	// docIndex is the documentation of a table index.
+	docIndex struct {
+		Name    string
+		Columns string
+		Unique  string
+		Primary string
+	}
+	// docForeignKey is the documentation of a table foreign key.
+	docForeignKey struct {
+		Name              string
+		Columns           string
+		ReferencedTable   string
+		ReferencedColumns string
+	}
+)
+
+func (c cGen) Doc(ctx context.Context, in cGenDocInput) (out *cGenDocOutput, err error) {
+	in.Format = gstr.ToLower(in.Format)
+	switch in.Format {
+	case "markdown", "html", "all":
+	default:
+		mlog.Fatalf(`unsupported documentation format "%s", it should be one of: markdown, html, all`, in.Format)
+	}
+	db := getDatabase(in.Link, in.Group)
+	if db == nil {
+		mlog.Fatal("database initialization failed")
+	}
+	tableNames, err := db.Tables(ctx)
+	if err != nil {
+		mlog.Fatalf("fetching tables failed: \n %v", err)
+	}
+	tableNames = filterTableNames(tableNames, in.Tables, in.TablesEx)
+	if len(tableNames) == 0 {
+		mlog.Fatal("no table matches the given table patterns")
+	}
+	tables, err := loadDatabaseSchema(ctx, db, tableNames)
+	if err != nil {
+		mlog.Fatalf("%+v", err)
+	}
+	data := &docData{
+		Title:     in.Title,
+		CreatedAt: gtime.Now().String(),
+		Tables:    make([]*docTable, 0, len(tables)),
+	}
+	for _, table := range tables {
+		data.Tables = append(data.Tables, getDocTable(table, db.GetConfig().Type, in))
+	}
+
+	if in.Format == "markdown" || in.Format == "all" {
+		path := gfile.Join(in.Path, in.Name+".md")
+		if err = gfile.PutContents(path, generateDocMarkdown(data)); err != nil {
+			mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+		}
+		mlog.Print("generated:", path)
+	}
+	if in.Format == "html" || in.Format == "all" {
+		path := gfile.Join(in.Path, in.Name+".html")
+		content, err := generateDocHtml(data)
+		if err != nil {
+			mlog.Fatalf(`generating html documentation failed: %+v`, err)
+		}
+		if err = gfile.PutContents(path, content); err != nil {
+			mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+		}
+		mlog.Print("generated:", path)
+	}
+	mlog.Print("done!")
+	return
+}
+
+// getDocTable converts the table schema to documentation,
+// in which the entity and field names are the same as "gen dao" generates.
+func getDocTable(table *schemaTable, dbType string, in cGenDocInput) *docTable {
+	newTableName := table.Name
+	for _, v := range gstr.SplitAndTrim(in.RemovePrefix, ",") {
+		newTableName = gstr.TrimLeftStr(newTableName, v, 1)
+	}
+	newTableName = in.Prefix + newTableName
+	var (
+		doc = &docTable{
+			Name:    table.Name,
+			Entity:  gstr.CaseCamel(newTableName),
+			Comment: formatComment(table.Comment),
+		}
+		structInput = generateStructDefinitionInput{
+			cGenDaoInternalInput: cGenDaoInternalInput{
+				cGenDaoInput: cGenDaoInput{
+					JsonCase:     in.JsonCase,
+					StdTime:      in.StdTime,
+					GJsonSupport: in.GJsonSupport,
+				},
+				DbType: dbType,
+			},
+		}
+	)
+	for i, column := range table.Columns {
+		var (
+			field = &gdb.TableField{
+				Index:   i,
+				Name:    column.Name,
+				Type:    column.Type,
+				Null:    column.Null,
+				Comment: column.Comment,
+			}
+			docCol = &docColumn{
+				Index:   i + 1,
+				Name:    column.Name,
+				Type:    column.Type,
+				Null:    "NO",
+				Extra:   column.Extra,
+				Comment: formatComment(column.Comment),
+				Field:   gstr.CaseCamel(column.Name),
+				GoType:  gstr.Trim(generateStructFieldDefinition(field, structInput)[1], " #"),
+				Json:    parseFieldDirectives(column.Comment).JsonTag(getJsonTagFromCase(column.Name, in.JsonCase)),
+				Key:     gstr.Join(getErdColumnKeys(table, column), ","),
+			}
+		)
+		if column.Null {
+			docCol.Null = "YES"
+		}
+		if column.Default != nil {
+			docCol.Default = *column.Default
+		}
+		doc.Columns = append(doc.Columns, docCol)
+	}
+	for _, index := range table.Indexes {
+		docIdx := &docIndex{
+			Name:    index.Name,
+			Columns: gstr.Join(index.Columns, ", "),
+		}
+		if index.Unique {
+			docIdx.Unique = "YES"
+		}
+		if index.Primary {
+			docIdx.Primary = "YES"
+		}
+		doc.Indexes = append(doc.Indexes, docIdx)
+	}
+	for _, foreignKey := range table.ForeignKeys {
+		doc.ForeignKeys = append(doc.ForeignKeys, &docForeignKey{
+			Name:              foreignKey.Name,
+			Columns:           gstr.Join(foreignKey.Columns, ", "),
+			ReferencedTable:   foreignKey.ReferencedTable,
+			ReferencedColumns: gstr.Join(foreignKey.ReferencedColumns, ", "),
+		})
+	}
+	return doc
+}
+
+// generateDocMarkdown generates and returns the documentation content in markdown.
+func generateDocMarkdown(data *docData) string {
+	var (
+		buffer = bytes.NewBuffer(nil)
+		cell   = func(s string) string {
+			return gstr.Replace(s, "|", `\|`)
+		}
+		code = func(s string) string {
+			if s == "" {
+				return ""
+			}
+			return "`" + cell(s) + "`"
+		}
+	)
+	buffer.WriteString(fmt.Sprintf("# %s\n\n", data.Title))
+	buffer.WriteString(fmt.Sprintf("> Generated by \"gf gen doc\" at %s\n\n", data.CreatedAt))
+	buffer.WriteString("## Tables\n\n")
+	buffer.WriteString("| Table | Comment |\n|---|---|\n")
+	for _, table := range data.Tables {
+		buffer.WriteString(fmt.Sprintf(
+			"| [%s](#%s) | %s |\n", cell(table.Name), gstr.ToLower(table.Name), cell(table.Comment),
+		))
+	}
+	for _, table := range data.Tables {
+		buffer.WriteString(fmt.Sprintf("\n## %s\n\n", table.Name))
+		if table.Comment != "" {
+			buffer.WriteString(table.Comment + "\n\n")
+		}
+		buffer.WriteString(fmt.Sprintf("Entity: `entity.%s`\n\n", table.Entity))
+		buffer.WriteString("| # | Column | Type | Null | Default | Key | Extra | Comment | Field | Go Type | JSON |\n")
+		buffer.WriteString("|---|---|---|---|---|---|---|---|---|---|---|\n")
+		for _, column := range table.Columns {
+			buffer.WriteString(fmt.Sprintf(
+				"| %d | %s | %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+				column.Index, code(column.Name), code(column.Type), column.Null, cell(column.Default),
+				column.Key, cell(column.Extra), cell(column.Comment),
+				code(column.Field), code(column.GoType), code(column.Json),
+			))
+		}
+		if len(table.Indexes) > 0 {
+			buffer.WriteString("\n### Indexes\n\n")
+			buffer.WriteString("| Name | Columns | Unique | Primary |\n|---|---|---|---|\n")
+			for _, index := range table.Indexes {
+				buffer.WriteString(fmt.Sprintf(
+					"| %s | %s | %s | %s |\n", code(index.Name), code(index.Columns), index.Unique, index.Primary,
+				))
+			}
+		}
+		if len(table.ForeignKeys) > 0 {
+			buffer.WriteString("\n### Foreign Keys\n\n")
+			buffer.WriteString("| Name | Columns | References |\n|---|---|---|\n")
+			for _, foreignKey := range table.ForeignKeys {
+				buffer.WriteString(fmt.Sprintf(
+					"| %s | %s | [%s](#%s) (%s) |\n",
+					code(foreignKey.Name), code(foreignKey.Columns),
+					cell(foreignKey.ReferencedTable), gstr.ToLower(foreignKey.ReferencedTable),
+					code(foreignKey.ReferencedColumns),
+				))
+			}
+		}
+	}
+	return buffer.String()
+}
+
+// generateDocHtml generates and returns the standalone documentation content in html.
+func generateDocHtml(data *docData) (string, error) {
+	tpl, err := template.New("doc").Parse(consts.TemplateGenDocHtmlContent)
+	if err != nil {
+		return "", err
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err = tpl.Execute(buffer, data); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
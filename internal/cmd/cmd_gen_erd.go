@@ -0,0 +1,447 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gproc"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gtag"
+)
+
+const (
+	cGenErdConfig = `gfcli.gen.erd`
+	cGenErdBrief  = `generate entity-relationship diagram of database tables`
+	cGenErdEg     = `
+gf gen erd
+gf gen erd -l "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+gf gen erd -f plantuml -o manifest/docs/erd.puml
+gf gen erd -f dot -t "user*,order*" -e "*_log" --svg
+`
+	cGenErdAd = `
+CONFIGURATION SUPPORT
+    Options are also supported by configuration file.
+    The configuration node name is "gfcli.gen.erd", for example(config.yaml):
+    gfcli:
+      gen:
+        erd:
+          link:     "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+          format:   "mermaid"
+          output:   "manifest/docs/erd.mmd"
+          tablesEx: "*_log"
+
+RELATIONSHIPS
+    The relationships are generated from the foreign keys of tables.
+    A relationship is "one to one" if the foreign key columns are unique, or else it is "one to many",
+    and it is optional if any of the foreign key columns can be null.
+    The relationships referencing the tables that are not included are ignored.
+`
+	cGenErdBriefOutput   = `output file path, default is "erd" with extension of the format in current working directory`
+	cGenErdBriefFormat   = `diagram format, which can be: mermaid, plantuml, dot. default is "mermaid"`
+	cGenErdBriefLink     = `database configuration, the same as the ORM configuration of GoFrame`
+	cGenErdBriefTables   = `only the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cGenErdBriefTablesEx = `excluding the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cGenErdBriefSvg      = `render the diagram to svg file using local "dot" command of Graphviz`
+	cGenErdBriefNoColumn = `only tables and relationships are generated, without columns`
+	cGenErdBriefGroup    = `
+specifying the configuration group name of database,
+it's not necessary and the default value is "default"
+`
+)
+
+const (
+	erdFormatMermaid  = `mermaid`
+	erdFormatPlantUml = `plantuml`
+	erdFormatDot      = `dot`
+)
+
+var (
+	// erdFormatExtensions is the file extensions of diagram formats.
+	erdFormatExtensions = map[string]string{
+		erdFormatMermaid:  "mmd",
+		erdFormatPlantUml: "puml",
+		erdFormatDot:      "dot",
+	}
+)
+
+func init() {
+	gtag.Sets(g.MapStrStr{
+		`cGenErdConfig`:        cGenErdConfig,
+		`cGenErdBrief`:         cGenErdBrief,
+		`cGenErdEg`:            cGenErdEg,
+		`cGenErdAd`:            cGenErdAd,
+		`cGenErdBriefOutput`:   cGenErdBriefOutput,
+		`cGenErdBriefFormat`:   cGenErdBriefFormat,
+		`cGenErdBriefLink`:     cGenErdBriefLink,
+		`cGenErdBriefTables`:   cGenErdBriefTables,
+		`cGenErdBriefTablesEx`: cGenErdBriefTablesEx,
+		`cGenErdBriefSvg`:      cGenErdBriefSvg,
+		`cGenErdBriefNoColumn`: cGenErdBriefNoColumn,
+		`cGenErdBriefGroup`:    cGenErdBriefGroup,
+	})
+}
+
+type (
+	cGenErdInput struct {
+		g.Meta   `name:"erd" config:"{cGenErdConfig}" brief:"{cGenErdBrief}" eg:"{cGenErdEg}" ad:"{cGenErdAd}"`
+		Output   string `name:"output"   short:"o" brief:"{cGenErdBriefOutput}"`
+		Format   string `name:"format"   short:"f" brief:"{cGenErdBriefFormat}" d:"mermaid"`
+		Link     string `name:"link"     short:"l" brief:"{cGenErdBriefLink}"`
+		Group    string `name:"group"    short:"g" brief:"{cGenErdBriefGroup}" d:"default"`
+		Tables   string `name:"tables"   short:"t" brief:"{cGenErdBriefTables}"`
+		TablesEx string `name:"tablesEx" short:"e" brief:"{cGenErdBriefTablesEx}"`
+		Svg      bool   `name:"svg"      short:"s" brief:"{cGenErdBriefSvg}"      orphan:"true"`
+		NoColumn bool   `name:"noColumn" short:"n" brief:"{cGenErdBriefNoColumn}" orphan:"true"`
+	}
+	cGenErdOutput struct{}
+
+	// erdRelationship is the relationship between two tables that is defined by foreign key.
+	erdRelationship struct {
+		Name       string            // Foreign key name.
+		Table      *schemaTable      // Table that has the foreign key.
+		Referenced *schemaTable      // Referenced table.
+		ForeignKey *schemaForeignKey // Foreign key definition.
+		Unique     bool              // Foreign key columns are unique, which makes it one to one relationship.
+		Optional   bool              // Any of the foreign key columns can be null.
+	}
+)
+
+func (c cGen) Erd(ctx context.Context, in cGenErdInput) (out *cGenErdOutput, err error) {
+	in.Format = gstr.ToLower(in.Format)
+	extension, ok := erdFormatExtensions[in.Format]
+	if !ok {
+		mlog.Fatalf(`unsupported diagram format "%s", it should be one of: mermaid, plantuml, dot`, in.Format)
+	}
+	if in.Output == "" {
+		in.Output = "erd." + extension
+	}
+	if in.Svg && gproc.SearchBinary("dot") == "" {
+		mlog.Fatal(`command "dot" not found in your environment, please install Graphviz first for svg rendering`)
+	}
+
+	db := getDatabase(in.Link, in.Group)
+	if db == nil {
+		mlog.Fatal("database initialization failed")
+	}
+	tableNames, err := db.Tables(ctx)
+	if err != nil {
+		mlog.Fatalf("fetching tables failed: \n %v", err)
+	}
+	tableNames = filterTableNames(tableNames, in.Tables, in.TablesEx)
+	if len(tableNames) == 0 {
+		mlog.Fatal("no table matches the given table patterns")
+	}
+	tables, err := loadDatabaseSchema(ctx, db, tableNames)
+	if err != nil {
+		mlog.Fatalf("%+v", err)
+	}
+	relationships := getErdRelationships(tables)
+
+	var content string
+	switch in.Format {
+	case erdFormatMermaid:
+		content = generateErdMermaid(tables, relationships, in)
+	case erdFormatPlantUml:
+		content = generateErdPlantUml(tables, relationships, in)
+	case erdFormatDot:
+		content = generateErdDot(tables, relationships, in)
+	}
+	if err = gfile.PutContents(in.Output, content); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", in.Output, err)
+	}
+	mlog.Print("generated:", in.Output)
+
+	// SVG rendering, which always uses DOT content.
+	if in.Svg {
+		var (
+			dotPath = in.Output
+			svgPath = gfile.Join(gfile.Dir(in.Output), gfile.Name(in.Output)+".svg")
+		)
+		if in.Format != erdFormatDot {
+			dotPath = gfile.Join(gfile.TempDir(), gfile.Name(in.Output)+".dot")
+			if err = gfile.PutContents(dotPath, generateErdDot(tables, relationships, in)); err != nil {
+				mlog.Fatalf("writing content to '%s' failed: %v", dotPath, err)
+			}
+			defer gfile.Remove(dotPath)
+		}
+		command := fmt.Sprintf(`dot -Tsvg "%s" -o "%s"`, dotPath, svgPath)
+		mlog.Debug(command)
+		if output, err := gproc.ShellExec(command); err != nil {
+			mlog.Print(output)
+			mlog.Fatalf(`rendering svg failed: %+v`, err)
+		}
+		mlog.Print("generated:", svgPath)
+	}
+	mlog.Print("done!")
+	return
+}
+
+// getErdRelationships returns the relationships between given tables from their foreign keys.
+func getErdRelationships(tables []*schemaTable) []*erdRelationship {
+	var (
+		tableMap      = make(map[string]*schemaTable)
+		relationships = make([]*erdRelationship, 0)
+	)
+	for _, table := range tables {
+		tableMap[table.Name] = table
+	}
+	for _, table := range tables {
+		for _, foreignKey := range table.ForeignKeys {
+			referenced, ok := tableMap[foreignKey.ReferencedTable]
+			if !ok {
+				mlog.Debugf(
+					`relationship "%s" of table "%s" is ignored as table "%s" is not included`,
+					foreignKey.Name, table.Name, foreignKey.ReferencedTable,
+				)
+				continue
+			}
+			relationship := &erdRelationship{
+				Name:       foreignKey.Name,
+				Table:      table,
+				Referenced: referenced,
+				ForeignKey: foreignKey,
+			}
+			for _, index := range table.Indexes {
+				if index.Unique && gstr.Join(index.Columns, ",") == gstr.Join(foreignKey.Columns, ",") {
+					relationship.Unique = true
+				}
+			}
+			for _, name := range foreignKey.Columns {
+				if column := table.Column(name); column != nil && column.Null {
+					relationship.Optional = true
+				}
+			}
+			relationships = append(relationships, relationship)
+		}
+	}
+	return relationships
+}
+
+// getErdColumnKeys returns the key markers of column, like: PK, FK, UK.
+func getErdColumnKeys(table *schemaTable, column *schemaColumn) []string {
+	keys := make([]string, 0)
+	for _, index := range table.Indexes {
+		if gstr.InArray(index.Columns, column.Name) {
+			if index.Primary && !gstr.InArray(keys, "PK") {
+				keys = append(keys, "PK")
+			}
+		}
+	}
+	for _, foreignKey := range table.ForeignKeys {
+		if gstr.InArray(foreignKey.Columns, column.Name) && !gstr.InArray(keys, "FK") {
+			keys = append(keys, "FK")
+		}
+	}
+	for _, index := range table.Indexes {
+		// Only single column unique index is marked.
+		if !index.Primary && index.Unique && len(index.Columns) == 1 && index.Columns[0] == column.Name {
+			if !gstr.InArray(keys, "UK") {
+				keys = append(keys, "UK")
+			}
+		}
+	}
+	return keys
+}
+
+// generateErdMermaid generates and returns the diagram content in Mermaid format.
+func generateErdMermaid(tables []*schemaTable, relationships []*erdRelationship, in cGenErdInput) string {
+	var (
+		buffer = bytes.NewBuffer(nil)
+		quote  = func(s string) string {
+			return `"` + gstr.Replace(formatComment(s), `"`, `'`) + `"`
+		}
+	)
+	buffer.WriteString("erDiagram\n")
+	for _, table := range tables {
+		if in.NoColumn {
+			buffer.WriteString(fmt.Sprintf("    %s {\n    }\n", erdIdentifier(table.Name)))
+			continue
+		}
+		buffer.WriteString(fmt.Sprintf("    %s {\n", erdIdentifier(table.Name)))
+		for _, column := range table.Columns {
+			// Mermaid only supports word type name, like: varchar_45.
+			columnType, _ := gregex.ReplaceString(`[^\w]+`, "_", column.Type)
+			line := fmt.Sprintf("        %s %s", gstr.Trim(columnType, "_"), erdIdentifier(column.Name))
+			if keys := getErdColumnKeys(table, column); len(keys) > 0 {
+				line += " " + gstr.Join(keys, ", ")
+			}
+			if column.Comment != "" {
+				line += " " + quote(column.Comment)
+			}
+			buffer.WriteString(line + "\n")
+		}
+		buffer.WriteString("    }\n")
+	}
+	for _, relationship := range relationships {
+		var (
+			left  = "||"
+			right = "o{"
+		)
+		if relationship.Optional {
+			left = "|o"
+		}
+		if relationship.Unique {
+			right = "o|"
+		}
+		buffer.WriteString(fmt.Sprintf(
+			"    %s %s--%s %s : %s\n",
+			erdIdentifier(relationship.Referenced.Name), left, right,
+			erdIdentifier(relationship.Table.Name), quote(relationship.Name),
+		))
+	}
+	return buffer.String()
+}
+
+// erdIdentifier returns the name that can be used as entity or attribute identifier in Mermaid and PlantUML.
+func erdIdentifier(name string) string {
+	name, _ = gregex.ReplaceString(`[^\w\-]+`, "_", name)
+	return name
+}
+
+// generateErdPlantUml generates and returns the diagram content in PlantUML format.
+func generateErdPlantUml(tables []*schemaTable, relationships []*erdRelationship, in cGenErdInput) string {
+	buffer := bytes.NewBuffer(nil)
+	buffer.WriteString("@startuml\n")
+	buffer.WriteString("hide circle\n")
+	buffer.WriteString("skinparam linetype ortho\n\n")
+	for _, table := range tables {
+		buffer.WriteString(fmt.Sprintf("entity \"%s\" as %s {\n", table.Name, erdIdentifier(table.Name)))
+		if table.Comment != "" {
+			buffer.WriteString(fmt.Sprintf("    ' %s\n", formatComment(table.Comment)))
+		}
+		if !in.NoColumn {
+			var (
+				primaries = make([]string, 0)
+				others    = make([]string, 0)
+			)
+			for _, column := range table.Columns {
+				var (
+					keys = getErdColumnKeys(table, column)
+					line = "    "
+				)
+				if !column.Null {
+					line += "* "
+				}
+				if gstr.InArray(keys, "PK") {
+					line += "**" + column.Name + "**"
+				} else {
+					line += column.Name
+				}
+				line += " : " + column.Type
+				for _, key := range keys {
+					line += " <<" + key + ">>"
+				}
+				if column.Comment != "" {
+					line += " -- " + formatComment(column.Comment)
+				}
+				if gstr.InArray(keys, "PK") {
+					primaries = append(primaries, line)
+				} else {
+					others = append(others, line)
+				}
+			}
+			for _, line := range primaries {
+				buffer.WriteString(line + "\n")
+			}
+			if len(primaries) > 0 && len(others) > 0 {
+				buffer.WriteString("    --\n")
+			}
+			for _, line := range others {
+				buffer.WriteString(line + "\n")
+			}
+		}
+		buffer.WriteString("}\n\n")
+	}
+	for _, relationship := range relationships {
+		var (
+			left  = "||"
+			right = "o{"
+		)
+		if relationship.Optional {
+			left = "|o"
+		}
+		if relationship.Unique {
+			right = "o|"
+		}
+		buffer.WriteString(fmt.Sprintf(
+			"%s %s--%s %s : %s\n",
+			erdIdentifier(relationship.Referenced.Name), left, right,
+			erdIdentifier(relationship.Table.Name), relationship.Name,
+		))
+	}
+	buffer.WriteString("@enduml\n")
+	return buffer.String()
+}
+
+// generateErdDot generates and returns the diagram content in Graphviz DOT format.
+func generateErdDot(tables []*schemaTable, relationships []*erdRelationship, in cGenErdInput) string {
+	var (
+		buffer = bytes.NewBuffer(nil)
+		quote  = func(s string) string {
+			return `"` + gstr.ReplaceByArray(s, g.SliceStr{`\`, `\\`, `"`, `\"`}) + `"`
+		}
+	)
+	buffer.WriteString("digraph erd {\n")
+	buffer.WriteString("    graph [rankdir=LR, fontname=\"Helvetica\"];\n")
+	buffer.WriteString("    node [shape=plain, fontname=\"Helvetica\", fontsize=10];\n")
+	buffer.WriteString("    edge [fontname=\"Helvetica\", fontsize=9, dir=both];\n\n")
+	for _, table := range tables {
+		buffer.WriteString(fmt.Sprintf("    %s [label=<\n", quote(table.Name)))
+		buffer.WriteString("        <table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n")
+		buffer.WriteString(fmt.Sprintf(
+			"            <tr><td bgcolor=\"#e0e0e0\" colspan=\"3\"><b>%s</b></td></tr>\n",
+			html.EscapeString(table.Name),
+		))
+		if table.Comment != "" {
+			buffer.WriteString(fmt.Sprintf(
+				"            <tr><td colspan=\"3\"><i>%s</i></td></tr>\n",
+				html.EscapeString(formatComment(table.Comment)),
+			))
+		}
+		if !in.NoColumn {
+			for _, column := range table.Columns {
+				name := html.EscapeString(column.Name)
+				keys := getErdColumnKeys(table, column)
+				if gstr.InArray(keys, "PK") {
+					name = "<u>" + name + "</u>"
+				}
+				buffer.WriteString(fmt.Sprintf(
+					"            <tr><td port=%s align=\"left\">%s</td><td align=\"left\">%s</td><td>%s</td></tr>\n",
+					quote(column.Name), name, html.EscapeString(column.Type), gstr.Join(keys, ","),
+				))
+			}
+		}
+		buffer.WriteString("        </table>\n    >];\n")
+	}
+	buffer.WriteString("\n")
+	for _, relationship := range relationships {
+		var (
+			from = quote(relationship.Table.Name)
+			to   = quote(relationship.Referenced.Name)
+			head = "teetee"   // Exactly one referenced record.
+			tail = "crowodot" // Zero or many records.
+		)
+		if !in.NoColumn && len(relationship.ForeignKey.Columns) > 0 && len(relationship.ForeignKey.ReferencedColumns) > 0 {
+			from += ":" + quote(relationship.ForeignKey.Columns[0])
+			to += ":" + quote(relationship.ForeignKey.ReferencedColumns[0])
+		}
+		if relationship.Optional {
+			head = "teeodot"
+		}
+		if relationship.Unique {
+			tail = "teeodot"
+		}
+		buffer.WriteString(fmt.Sprintf(
+			"    %s -> %s [label=%s, arrowhead=%s, arrowtail=%s];\n",
+			from, to, quote(relationship.Name), head, tail,
+		))
+	}
+	buffer.WriteString("}\n")
+	return buffer.String()
+}
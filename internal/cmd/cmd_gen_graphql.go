@@ -0,0 +1,417 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gtag"
+)
+
+const (
+	cGenGraphqlConfig = `gfcli.gen.graphql`
+	cGenGraphqlBrief  = `generate GraphQL schema definitions from database tables`
+	cGenGraphqlEg     = `
+gf gen graphql
+gf gen graphql -l "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+gf gen graphql -p ./manifest/graphql -a schema -t "user*,order*"
+gf gen graphql -r gf_
+`
+	cGenGraphqlAd = `
+CONFIGURATION SUPPORT
+    Options are also supported by configuration file.
+    The configuration node name is "gfcli.gen.graphql", for example(config.yaml):
+    gfcli:
+      gen:
+        graphql:
+          link:         "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+          path:         "manifest/graphql"
+          removePrefix: "gf_"
+    It's suggested using the same "prefix", "removePrefix", "jsonCase" and "gJsonSupport" options as "gf gen dao",
+    so that the type names and field names match the generated entities.
+
+SCHEMA DEFINITIONS
+    For each table, it generates:
+    type {Entity}               object type of entity, the fields of primary key are of type "ID"
+    input Create{Entity}Input   input type for creating, excluding the auto increment columns
+                                and the columns "created_at", "updated_at", "deleted_at" which are maintained by ORM,
+                                the columns having default value or being nullable are optional
+    input Update{Entity}Input   input type for updating, in which only the fields of primary key are required
+    The input types are not generated if they have no fields, like the create input of table having only
+    auto increment and auto time columns.
+    enum {Entity}{Field}        enum type of enum column and column having "@enum" directive
+    The column comments are used as descriptions, and the fields of columns having "@deprecated" directive
+    are marked with "@deprecated". The custom scalars "Int64", "DateTime" and "JSON" are declared if they are used.
+`
+	cGenGraphqlBriefPath         = `directory path for generated GraphQL schema file`
+	cGenGraphqlBriefName         = `file name of generated GraphQL schema file without extension`
+	cGenGraphqlBriefLink         = `database configuration, the same as the ORM configuration of GoFrame`
+	cGenGraphqlBriefTables       = `only the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cGenGraphqlBriefTablesEx     = `excluding the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cGenGraphqlBriefPrefix       = `add prefix for all table names, the same as "gf gen dao"`
+	cGenGraphqlBriefRemovePrefix = `remove specified prefix of the table, multiple prefix separated with ',', the same as "gf gen dao"`
+	cGenGraphqlBriefJsonCase     = `field name case, the same as "gf gen dao", default is "CamelLower"`
+	cGenGraphqlBriefGJsonSupport = `use scalar JSON for json fields like *gjson.Json of "gf gen dao", or else String`
+	cGenGraphqlBriefForce        = `overwrite the generated file even if it was modified after last generating`
+	cGenGraphqlBriefGroup        = `
+specifying the configuration group name of database,
+it's not necessary and the default value is "default"
+`
+)
+
+const (
+	graphqlScalarInt64    = "Int64"
+	graphqlScalarDateTime = "DateTime"
+	graphqlScalarJson     = "JSON"
+)
+
+// graphqlScalarDescriptions are the descriptions of custom scalars.
+var graphqlScalarDescriptions = map[string]string{
+	graphqlScalarInt64:    "64-bit integer, which exceeds the range of Int.",
+	graphqlScalarDateTime: "Date and time string, like: 2006-01-02 15:04:05.",
+	graphqlScalarJson:     "Arbitrary JSON value.",
+}
+
+// graphqlAutoTimeColumns are the columns maintained by ORM automatically, which are excluded from create input.
+var graphqlAutoTimeColumns = []string{"created_at", "updated_at", "deleted_at"}
+
+func init() {
+	gtag.Sets(g.MapStrStr{
+		`cGenGraphqlConfig`:            cGenGraphqlConfig,
+		`cGenGraphqlBrief`:             cGenGraphqlBrief,
+		`cGenGraphqlEg`:                cGenGraphqlEg,
+		`cGenGraphqlAd`:                cGenGraphqlAd,
+		`cGenGraphqlBriefPath`:         cGenGraphqlBriefPath,
+		`cGenGraphqlBriefName`:         cGenGraphqlBriefName,
+		`cGenGraphqlBriefLink`:         cGenGraphqlBriefLink,
+		`cGenGraphqlBriefTables`:       cGenGraphqlBriefTables,
+		`cGenGraphqlBriefTablesEx`:     cGenGraphqlBriefTablesEx,
+		`cGenGraphqlBriefPrefix`:       cGenGraphqlBriefPrefix,
+		`cGenGraphqlBriefRemovePrefix`: cGenGraphqlBriefRemovePrefix,
+		`cGenGraphqlBriefJsonCase`:     cGenGraphqlBriefJsonCase,
+		`cGenGraphqlBriefGJsonSupport`: cGenGraphqlBriefGJsonSupport,
+		`cGenGraphqlBriefForce`:        cGenGraphqlBriefForce,
+		`cGenGraphqlBriefGroup`:        cGenGraphqlBriefGroup,
+	})
+}
+
+type (
+	cGenGraphqlInput struct {
+		g.Meta       `name:"graphql" config:"{cGenGraphqlConfig}" brief:"{cGenGraphqlBrief}" eg:"{cGenGraphqlEg}" ad:"{cGenGraphqlAd}"`
+		Path         string `name:"path"         short:"p" brief:"{cGenGraphqlBriefPath}" d:"manifest/graphql"`
+		Name         string `name:"name"         short:"a" brief:"{cGenGraphqlBriefName}" d:"schema"`
+		Link         string `name:"link"         short:"l" brief:"{cGenGraphqlBriefLink}"`
+		Group        string `name:"group"        short:"g" brief:"{cGenGraphqlBriefGroup}" d:"default"`
+		Tables       string `name:"tables"       short:"t" brief:"{cGenGraphqlBriefTables}"`
+		TablesEx     string `name:"tablesEx"     short:"e" brief:"{cGenGraphqlBriefTablesEx}"`
+		Prefix       string `name:"prefix"       short:"x" brief:"{cGenGraphqlBriefPrefix}"`
+		RemovePrefix string `name:"removePrefix" short:"r" brief:"{cGenGraphqlBriefRemovePrefix}"`
+		JsonCase     string `name:"jsonCase"     short:"j" brief:"{cGenGraphqlBriefJsonCase}" d:"CamelLower"`
+		GJsonSupport bool   `name:"gJsonSupport" short:"n" brief:"{cGenGraphqlBriefGJsonSupport}" orphan:"true"`
+		Force        bool   `name:"force"                  brief:"{cGenGraphqlBriefForce}" orphan:"true"`
+	}
+	cGenGraphqlOutput struct{}
+
+	// graphqlType is the GraphQL object type of a table entity.
+	graphqlType struct {
+		Name        string
+		Table       string
+		Description string
+		Fields      []*graphqlField
+		Enums       []*graphqlEnum
+	}
+	// graphqlField is the GraphQL field of an entity field.
+	graphqlField struct {
+		Name        string
+		Type        string
+		NonNull     bool // NonNull specifies the column is not nullable.
+		Primary     bool // Primary specifies the column is part of primary key.
+		AutoTime    bool // AutoTime specifies the column is maintained by ORM automatically.
+		Creatable   bool // Creatable specifies the field is in create input.
+		Required    bool // Required specifies the field is required in create input.
+		Description string
+		Deprecated  bool
+	}
+	// graphqlEnum is the GraphQL enum type of an enum column.
+	graphqlEnum struct {
+		Name   string
+		Values []*graphqlEnumValue
+	}
+	// graphqlEnumValue is the GraphQL enum value of an enum column value.
+	graphqlEnumValue struct {
+		Name        string
+		Description string // Description is the original value if the name is not its upper case.
+	}
+)
+
+func (c cGen) Graphql(ctx context.Context, in cGenGraphqlInput) (out *cGenGraphqlOutput, err error) {
+	db := getDatabase(in.Link, in.Group)
+	if db == nil {
+		mlog.Fatal("database initialization failed")
+	}
+	tableNames, err := db.Tables(ctx)
+	if err != nil {
+		mlog.Fatalf("fetching tables failed: \n %v", err)
+	}
+	tableNames = filterTableNames(tableNames, in.Tables, in.TablesEx)
+	if len(tableNames) == 0 {
+		mlog.Fatal("no table matches the given table patterns")
+	}
+	tables, err := loadDatabaseSchema(ctx, db, tableNames)
+	if err != nil {
+		mlog.Fatalf("%+v", err)
+	}
+	types := make([]*graphqlType, 0, len(tables))
+	for _, table := range tables {
+		types = append(types, getGraphqlType(table, db.GetConfig().Type, in))
+	}
+
+	beginGeneratedManifest(getGeneratedManifestScope("gen graphql", in.Path, in.Link, in.Group))
+	defer endGeneratedManifest(true)
+
+	path := gfile.Join(in.Path, in.Name+".graphql")
+	if err = putGeneratedContents(path, generateGraphqlSchema(types), in.Force); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+	}
+	mlog.Print("generated:", path)
+	mlog.Print("done!")
+	return
+}
+
+// getGraphqlType converts the table schema to GraphQL type,
+// in which the type and field names are the same as the entity and json of "gen dao".
+func getGraphqlType(table *schemaTable, dbType string, in cGenGraphqlInput) *graphqlType {
+	newTableName := table.Name
+	for _, v := range gstr.SplitAndTrim(in.RemovePrefix, ",") {
+		newTableName = gstr.TrimLeftStr(newTableName, v, 1)
+	}
+	newTableName = in.Prefix + newTableName
+	var (
+		gt = &graphqlType{
+			Name:        gstr.CaseCamel(newTableName),
+			Table:       table.Name,
+			Description: formatComment(table.Comment),
+		}
+		primaryColumns []string
+		structInput    = generateStructDefinitionInput{
+			cGenDaoInternalInput: cGenDaoInternalInput{
+				cGenDaoInput: cGenDaoInput{
+					JsonCase:     in.JsonCase,
+					GJsonSupport: in.GJsonSupport,
+				},
+				DbType: dbType,
+			},
+		}
+	)
+	if primary := table.PrimaryKey(); primary != nil {
+		primaryColumns = primary.Columns
+	}
+	for i, column := range table.Columns {
+		directives := parseFieldDirectives(column.Comment)
+		if directives.JsonIgnore {
+			continue
+		}
+		var (
+			field = &gdb.TableField{
+				Index:   i,
+				Name:    column.Name,
+				Type:    column.Type,
+				Null:    column.Null,
+				Comment: column.Comment,
+			}
+			goType   = gstr.Trim(generateStructFieldDefinition(field, structInput)[1], " #")
+			jsonName = gstr.Split(directives.JsonTag(getJsonTagFromCase(column.Name, in.JsonCase)), ",")[0]
+			item     = &graphqlField{
+				Name:        getGraphqlName(jsonName),
+				Type:        getGraphqlFieldType(goType),
+				NonNull:     !column.Null,
+				Primary:     gstr.InArray(primaryColumns, column.Name),
+				Description: formatComment(column.Comment),
+				Deprecated:  directives.Deprecated,
+			}
+		)
+		item.AutoTime = gstr.InArray(graphqlAutoTimeColumns, gstr.ToLower(column.Name))
+		item.Creatable = !item.AutoTime && !gstr.ContainsI(column.Extra, "auto_increment")
+		item.Required = item.Creatable && !column.Null && column.Default == nil
+		if item.Primary {
+			item.Type = "ID"
+		}
+		if enumValues := getColumnEnumValues(column.Type, directives); len(enumValues) > 0 {
+			enum := &graphqlEnum{
+				Name: gt.Name + gstr.CaseCamel(column.Name),
+			}
+			for _, v := range enumValues {
+				enum.Values = append(enum.Values, getGraphqlEnumValue(v))
+			}
+			gt.Enums = append(gt.Enums, enum)
+			item.Type = enum.Name
+		}
+		gt.Fields = append(gt.Fields, item)
+	}
+	return gt
+}
+
+// getGraphqlFieldType returns the GraphQL type of golang type `goType` generated for entity field.
+func getGraphqlFieldType(goType string) string {
+	switch goType {
+	case "int", "int8", "int16", "int32", "uint8", "uint16":
+		return "Int"
+	case "int64", "uint", "uint32", "uint64":
+		return graphqlScalarInt64
+	case "float32", "float64":
+		return "Float"
+	case "bool":
+		return "Boolean"
+	case "*gtime.Time", "time.Time":
+		return graphqlScalarDateTime
+	case "*gjson.Json":
+		return graphqlScalarJson
+	default:
+		return "String"
+	}
+}
+
+// getGraphqlName returns the valid GraphQL name of `name`, which replaces the invalid chars with '_'.
+func getGraphqlName(name string) string {
+	name, _ = gregex.ReplaceString(`[^_0-9A-Za-z]`, "_", name)
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// getGraphqlEnumValue returns the GraphQL enum value of column enum value `value`,
+// which is in screaming snake case, like: "active" to "ACTIVE".
+func getGraphqlEnumValue(value string) *graphqlEnumValue {
+	enumValue := &graphqlEnumValue{
+		Name: getGraphqlName(gstr.CaseSnakeScreaming(value)),
+	}
+	switch enumValue.Name {
+	case "TRUE", "FALSE", "NULL":
+		enumValue.Name += "_"
+	}
+	if enumValue.Name != gstr.ToUpper(value) {
+		enumValue.Description = value
+	}
+	return enumValue
+}
+
+// generateGraphqlSchema generates and returns the GraphQL schema definitions of `types`.
+func generateGraphqlSchema(types []*graphqlType) string {
+	var (
+		buffer  = bytes.NewBuffer(nil)
+		scalars = make(map[string]bool)
+	)
+	for _, gt := range types {
+		for _, item := range gt.Fields {
+			if _, ok := graphqlScalarDescriptions[item.Type]; ok {
+				scalars[item.Type] = true
+			}
+		}
+	}
+	buffer.WriteString("# ==========================================================================\n")
+	buffer.WriteString("# Code generated by GoFrame CLI tool. DO NOT EDIT.\n")
+	buffer.WriteString("# ==========================================================================\n")
+	for _, name := range []string{graphqlScalarInt64, graphqlScalarDateTime, graphqlScalarJson} {
+		if scalars[name] {
+			buffer.WriteString(fmt.Sprintf(
+				"\n%sscalar %s\n", getGraphqlDescription(graphqlScalarDescriptions[name], ""), name,
+			))
+		}
+	}
+	for _, gt := range types {
+		for _, enum := range gt.Enums {
+			buffer.WriteString(fmt.Sprintf("\nenum %s {\n", enum.Name))
+			for _, value := range enum.Values {
+				buffer.WriteString(getGraphqlDescription(value.Description, "  "))
+				buffer.WriteString(fmt.Sprintf("  %s\n", value.Name))
+			}
+			buffer.WriteString("}\n")
+		}
+
+		// Object type.
+		description := fmt.Sprintf("Entity of table %s.", gt.Table)
+		if gt.Description != "" {
+			description = gt.Description + "\n" + description
+		}
+		buffer.WriteString("\n" + getGraphqlDescription(description, ""))
+		buffer.WriteString(fmt.Sprintf("type %s {\n", gt.Name))
+		for _, item := range gt.Fields {
+			buffer.WriteString(getGraphqlDescription(item.Description, "  "))
+			line := fmt.Sprintf("  %s: %s", item.Name, item.Type)
+			if item.NonNull {
+				line += "!"
+			}
+			if item.Deprecated {
+				line += " @deprecated"
+			}
+			buffer.WriteString(line + "\n")
+		}
+		buffer.WriteString("}\n")
+
+		// Create and update input types, which are not generated if they have no fields,
+		// like the create input of table having only auto increment and auto time columns,
+		// as input type without fields is invalid.
+		var (
+			createFields = make([]string, 0)
+			updateFields = make([]string, 0)
+		)
+		for _, item := range gt.Fields {
+			if item.AutoTime {
+				continue
+			}
+			line := getGraphqlDescription(item.Description, "  ") + fmt.Sprintf("  %s: %s", item.Name, item.Type)
+			if item.Creatable {
+				if item.Required {
+					createFields = append(createFields, line+"!")
+				} else {
+					createFields = append(createFields, line)
+				}
+			}
+			if item.Primary {
+				updateFields = append(updateFields, line+"!")
+			} else {
+				updateFields = append(updateFields, line)
+			}
+		}
+		buffer.WriteString(getGraphqlInput("Create"+gt.Name+"Input", "Input for creating "+gt.Name+".", createFields))
+		buffer.WriteString(getGraphqlInput("Update"+gt.Name+"Input", "Input for updating "+gt.Name+".", updateFields))
+	}
+	return buffer.String()
+}
+
+// getGraphqlInput returns the input type definition of `name` with field lines `fields`,
+// it returns empty string if `fields` is empty.
+func getGraphqlInput(name, description string, fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(
+		"\n%sinput %s {\n%s\n}\n", getGraphqlDescription(description, ""), name, gstr.Join(fields, "\n"),
+	)
+}
+
+// getGraphqlDescription returns the description line of `description` with `indent`,
+// which is block string if it has multiple lines. It returns empty string if `description` is empty.
+func getGraphqlDescription(description, indent string) string {
+	if description == "" {
+		return ""
+	}
+	if !strings.Contains(description, "\n") {
+		description = gstr.Replace(gstr.Replace(description, `\`, `\\`), `"`, `\"`)
+		return fmt.Sprintf("%s\"%s\"\n", indent, description)
+	}
+	description = gstr.Replace(description, `"""`, `\"""`)
+	lines := strings.Split(description, "\n")
+	for i, line := range lines {
+		lines[i] = indent + line
+	}
+	return fmt.Sprintf("%s\"\"\"\n%s\n%s\"\"\"\n", indent, gstr.Join(lines, "\n"), indent)
+}
@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf-cli/v2/utility/utils"
+	"github.com/gogf/gf/v2/crypto/gmd5"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+const (
+	// generatedManifestFile is the manifest file in working directory,
+	// which records the files generated by the generating commands with their checksums.
+	generatedManifestFile = `.gf-gen-manifest.json`
+)
+
+type (
+	// generatedManifest records the generated files and generating runs.
+	generatedManifest struct {
+		Files  map[string]*generatedManifestFileItem `json:"files"`  // Generated files by their relative paths.
+		Scopes map[string]int64                      `json:"scopes"` // The latest full run of each generating scope.
+	}
+	// generatedManifestFileItem is the record of a generated file.
+	generatedManifestFileItem struct {
+		Checksum  string `json:"checksum"`  // Checksum of the generated content.
+		Scope     string `json:"scope"`     // Scope of the generating, like: "gen dao internal".
+		Run       int64  `json:"run"`       // Run identifier of the generating.
+		UpdatedAt string `json:"updatedAt"` // Generating time.
+	}
+)
+
+// generatedFileWrite is a pending write of generated file in current generating run.
+type generatedFileWrite struct {
+	Path         string // Path of the generated file.
+	ManifestPath string // Manifest path of the generated file.
+	Content      string // Generated content, which is formatted for go file.
+	Force        bool   // Whether overwriting the manually modified file.
+	Custom       bool   // Whether the file is for custom codes, which is neither checked nor recorded.
+}
+
+var (
+	// currentManifest is the manifest loaded once for current generating run,
+	// which is changed in memory and saved when the run ends.
+	currentManifest *generatedManifest
+	// currentManifestScope is the scope of current generating run.
+	currentManifestScope string
+	// currentManifestRun is the identifier of current generating run.
+	currentManifestRun int64
+	// currentManifestFiles are the manifest paths of files recorded in current generating run.
+	currentManifestFiles []string
+	// currentManifestWrites are the pending writes of current generating run,
+	// which are written only after all their target files are checked.
+	currentManifestWrites []*generatedFileWrite
+)
+
+// beginGeneratedManifest begins a generating run of `scope`,
+// the generated files of following putGeneratedContents/recordGeneratedFile calls are recorded for the scope.
+func beginGeneratedManifest(scope string) {
+	currentManifest = loadGeneratedManifest()
+	currentManifestScope = scope
+	currentManifestRun = gtime.TimestampNano()
+	currentManifestFiles = nil
+	currentManifestWrites = nil
+}
+
+// endGeneratedManifest ends current generating run, it writes the pending files and saves the manifest.
+// If `full` is true, which means all files of the scope are generated in this run,
+// the files of the scope generated by previous runs are considered orphaned.
+func endGeneratedManifest(full bool) {
+	if currentManifest == nil {
+		return
+	}
+	flushGeneratedFiles()
+	if full && currentManifestScope != "" {
+		currentManifest.Scopes[currentManifestScope] = currentManifestRun
+	}
+	saveGeneratedManifest(currentManifest)
+	currentManifest = nil
+	currentManifestScope = ""
+	currentManifestRun = 0
+	currentManifestFiles = nil
+	currentManifestWrites = nil
+}
+
+// getGeneratedManifestScope returns the scope name of generator and its output path.
+// The `identities` distinguishes the generating of the same output path, like database link,
+// which are hashed as they might contain credentials.
+func getGeneratedManifestScope(generator, path string, identities ...string) string {
+	scope := fmt.Sprintf(`%s %s`, generator, getGeneratedManifestPath(path))
+	if len(identities) > 0 {
+		scope += " " + gmd5.MustEncryptString(gstr.Join(identities, ","))[:8]
+	}
+	return scope
+}
+
+// putGeneratedContents writes the generated `content` to `path` and records it to the manifest.
+// The go content is formatted before its checksum is calculated.
+//
+// In a generating run, the writing is pending until the run ends or flushGeneratedFiles is called,
+// so that no file is written if any target file was modified after last generating.
+// Or else it is written at once and returns error if the file was modified, unless `force` is true.
+func putGeneratedContents(path, content string, force bool) error {
+	if gfile.ExtName(path) == "go" {
+		formatted, err := utils.GoFmtSource([]byte(content))
+		if err != nil {
+			return gerror.Wrapf(err, `format go file "%s" failed`, path)
+		}
+		content = string(formatted)
+	}
+	return putGeneratedRawContents(path, content, force)
+}
+
+// putGeneratedRawContents writes the generated `content` to `path` as it is and records it to the manifest,
+// which is like putGeneratedContents but the go content is not formatted.
+func putGeneratedRawContents(path, content string, force bool) error {
+	if currentManifest == nil {
+		if err := checkGeneratedFile(path, force); err != nil {
+			return err
+		}
+		if err := gfile.PutContents(path, content); err != nil {
+			return err
+		}
+		recordGeneratedFile(path)
+		return nil
+	}
+	addGeneratedFileWrite(&generatedFileWrite{
+		Path:         path,
+		ManifestPath: getGeneratedManifestPath(path),
+		Content:      content,
+		Force:        force,
+	})
+	return nil
+}
+
+// putCustomContents writes the `content` to `path` of file for custom codes, like the dao index file,
+// which is written only if the file does not exist unless `overwrite` is true.
+// It is not recorded to the manifest as it is supposed to be modified,
+// but it is pending in a generating run like putGeneratedContents.
+// It returns whether the content is written.
+func putCustomContents(path, content string, overwrite bool) (bool, error) {
+	if _, ok := getGeneratedContents(path); ok && !overwrite {
+		return false, nil
+	}
+	if gfile.ExtName(path) == "go" {
+		formatted, err := utils.GoFmtSource([]byte(content))
+		if err != nil {
+			return false, gerror.Wrapf(err, `format go file "%s" failed`, path)
+		}
+		content = string(formatted)
+	}
+	if currentManifest == nil {
+		return true, gfile.PutContents(path, content)
+	}
+	addGeneratedFileWrite(&generatedFileWrite{
+		Path:         path,
+		ManifestPath: getGeneratedManifestPath(path),
+		Content:      content,
+		Custom:       true,
+	})
+	return true, nil
+}
+
+// addGeneratedFileWrite adds the pending write `write` to current generating run,
+// the latest content wins if the same file is written more than once.
+func addGeneratedFileWrite(write *generatedFileWrite) {
+	for _, v := range currentManifestWrites {
+		if v.ManifestPath == write.ManifestPath {
+			v.Content = write.Content
+			v.Force = v.Force || write.Force
+			return
+		}
+	}
+	currentManifestWrites = append(currentManifestWrites, write)
+	if !write.Custom {
+		currentManifestFiles = append(currentManifestFiles, write.ManifestPath)
+	}
+}
+
+// discardGeneratedFiles discards the pending writes of current generating run,
+// which is called if the generating fails, so that nothing is written.
+func discardGeneratedFiles() {
+	for _, write := range currentManifestWrites {
+		for i, path := range currentManifestFiles {
+			if path == write.ManifestPath {
+				currentManifestFiles = append(currentManifestFiles[:i], currentManifestFiles[i+1:]...)
+				break
+			}
+		}
+	}
+	currentManifestWrites = nil
+}
+
+// getGeneratedContents returns the content of file `path`, which is the pending content in a generating run,
+// so that the files generated in the run can be read before they are written. It returns false if no such file.
+func getGeneratedContents(path string) (string, bool) {
+	manifestPath := getGeneratedManifestPath(path)
+	for _, write := range currentManifestWrites {
+		if write.ManifestPath == manifestPath {
+			return write.Content, true
+		}
+	}
+	if !gfile.Exists(path) {
+		return "", false
+	}
+	return gfile.GetContents(path), true
+}
+
+// flushGeneratedFiles writes the pending files of current generating run and records them to the manifest.
+// All the target files are checked before writing anything, it fails with all the modified files if any.
+func flushGeneratedFiles() {
+	var (
+		writes   = currentManifestWrites
+		messages = make([]string, 0)
+	)
+	currentManifestWrites = nil
+	for _, write := range writes {
+		if write.Custom {
+			continue
+		}
+		if err := checkGeneratedFile(write.Path, write.Force); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) > 0 {
+		mlog.Fatalf("nothing is written as:\n%s", gstr.Join(messages, "\n"))
+	}
+	for _, write := range writes {
+		if err := gfile.PutContents(write.Path, write.Content); err != nil {
+			// The manifest is saved for the files already written.
+			saveGeneratedManifest(currentManifest)
+			mlog.Fatalf("writing content to '%s' failed: %v", write.Path, err)
+		}
+		if write.Custom {
+			continue
+		}
+		setGeneratedFileItem(currentManifest, write.ManifestPath, getGeneratedContentChecksum(write.Content))
+	}
+}
+
+// checkGeneratedFile checks whether the generated file `path` was modified after last generating.
+// It returns error if the file was modified, or it just prints warning if `force` is true.
+func checkGeneratedFile(path string, force bool) error {
+	manifest := currentManifest
+	if manifest == nil {
+		manifest = loadGeneratedManifest()
+	}
+	item := manifest.Files[getGeneratedManifestPath(path)]
+	if item == nil || !gfile.Exists(path) || getGeneratedFileChecksum(path) == item.Checksum {
+		return nil
+	}
+	if force {
+		mlog.Printf(`overwriting manually modified generated file "%s"`, path)
+		return nil
+	}
+	return gerror.Newf(
+		`generated file "%s" was modified after last generating, use "--force" option to overwrite it`, path,
+	)
+}
+
+// recordGeneratedFile records the checksum of generated file `path` to the manifest.
+// The manifest is saved at once if it is not in a generating run.
+func recordGeneratedFile(path string) {
+	var (
+		manifest     = currentManifest
+		manifestPath = getGeneratedManifestPath(path)
+	)
+	if manifest == nil {
+		manifest = loadGeneratedManifest()
+		defer saveGeneratedManifest(manifest)
+	}
+	setGeneratedFileItem(manifest, manifestPath, getGeneratedFileChecksum(path))
+	currentManifestFiles = append(currentManifestFiles, manifestPath)
+}
+
+// setGeneratedFileItem sets the record of manifest path `manifestPath` with `checksum` for current generating run.
+func setGeneratedFileItem(manifest *generatedManifest, manifestPath, checksum string) {
+	manifest.Files[manifestPath] = &generatedManifestFileItem{
+		Checksum:  checksum,
+		Scope:     currentManifestScope,
+		Run:       currentManifestRun,
+		UpdatedAt: gtime.Now().String(),
+	}
+}
+
+// keepGeneratedFiles records the files of manifest paths `paths` to current generating run without regenerating,
+// so that they are not considered orphaned by the full generating. Their checksums are not changed.
+func keepGeneratedFiles(paths []string) {
+	if currentManifest == nil {
+		return
+	}
+	for _, path := range paths {
+		if item, ok := currentManifest.Files[path]; ok {
+			item.Scope = currentManifestScope
+			item.Run = currentManifestRun
+			currentManifestFiles = append(currentManifestFiles, path)
+		}
+	}
+}
+
+// loadGeneratedManifest loads and returns the manifest from working directory.
+func loadGeneratedManifest() *generatedManifest {
+	manifest := &generatedManifest{}
+	if gfile.Exists(generatedManifestFile) {
+		if err := json.Unmarshal(gfile.GetBytes(generatedManifestFile), manifest); err != nil {
+			mlog.Fatalf(`invalid manifest file "%s": %v`, generatedManifestFile, err)
+		}
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]*generatedManifestFileItem)
+	}
+	if manifest.Scopes == nil {
+		manifest.Scopes = make(map[string]int64)
+	}
+	return manifest
+}
+
+// saveGeneratedManifest saves the manifest to working directory.
+func saveGeneratedManifest(manifest *generatedManifest) {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		mlog.Fatalf(`encoding manifest failed: %v`, err)
+	}
+	if err = gfile.PutBytes(generatedManifestFile, content); err != nil {
+		mlog.Fatalf(`writing manifest file "%s" failed: %v`, generatedManifestFile, err)
+	}
+}
+
+// getGeneratedManifestPath returns the path relative to working directory in slash format,
+// which is used as the key of manifest.
+func getGeneratedManifestPath(path string) string {
+	if realPath := gfile.RealPath(path); realPath != "" {
+		path = realPath
+	} else {
+		path = gfile.Abs(path)
+	}
+	path = gstr.TrimLeftStr(path, gfile.Pwd())
+	path = gstr.Replace(path, gfile.Separator, "/")
+	return gstr.TrimLeft(path, "/")
+}
+
+// getGeneratedFileChecksum returns the sha256 checksum of file `path`.
+func getGeneratedFileChecksum(path string) string {
+	return getGeneratedContentChecksum(gfile.GetContents(path))
+}
+
+// getGeneratedContentChecksum returns the sha256 checksum of generated `content`.
+func getGeneratedContentChecksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -9,9 +9,9 @@ import (
 	"github.com/gogf/gf-cli/v2/internal/consts"
 	"github.com/gogf/gf-cli/v2/utility/mlog"
 	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gfile"
-	"github.com/gogf/gf/v2/os/gtime"
 	"github.com/gogf/gf/v2/text/gregex"
 	"github.com/gogf/gf/v2/text/gstr"
 	"github.com/gogf/gf/v2/util/gconv"
@@ -33,32 +33,81 @@ gf gen pbentity -r user_
 CONFIGURATION SUPPORT
     Options are also supported by configuration file.
     It's suggested using configuration file instead of command line arguments making producing. 
-    The configuration node name is "gf.gen.pbentity", which also supports multiple databases, for example(config.yaml):
+    The configuration node name is "gfcli.gen.pbentity", which also supports multiple databases, for example(config.yaml):
     gfcli:
       gen:
-      - pbentity:
-            link:    "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
-            path:    "protocol/demos/entity"
-            tables:  "order,products"
-            package: "demos"
-      - pbentity:
-            link:    "mysql:root:12345678@tcp(127.0.0.1:3306)/primary"
-            path:    "protocol/demos/entity"
-            prefix:  "primary_"
-            tables:  "user, userDetail"
-            package: "demos"
-            option:  |
-			  option go_package    = "protobuf/demos";
-			  option java_package  = "protobuf/demos";
-			  option php_namespace = "protobuf/demos";
+        pbentity:
+        - link:     "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+          path:     "protocol/demos/entity"
+          tables:   "order,products"
+          package:  "demos"
+        - group:    "primary"
+          path:     "protocol/primary/entity"
+          prefix:   "primary_"
+          tablesEx: "user_log_*"
+          package:  "primary"
+          option:   |
+            option go_package    = "protobuf/primary";
+            option java_package  = "protobuf/primary";
+            option php_namespace = "protobuf/primary";
+    Each configuration entry has its own options, and the options of command line are used as default values.
+    The "link" supports environment variable and secret file references, and structured configuration,
+    which are the same as "gf gen dao", please refer to "gf gen dao -h" for details.
+    The column comment directives "@json:-", "@omitempty", "@enum" and "@deprecated" are also supported,
+    which are applied to the json tag, comment and options of message field.
+
+STYLES
+    The style of generated proto files is specified by option "style":
+    gogo   the json tags are defined by "(gogoproto.jsontag)" option, which requires gogo protobuf
+    plain  the standard proto3 for protoc-gen-go, the json names are defined by "json_name" option,
+           and the "go_package" option is computed from the module name in go.mod if it is not given
+
+FIELD NUMBERS
+    The field numbers of existing proto files are kept across generating for wire compatibility,
+    the new columns are appended with fresh numbers, and the numbers and names of removed columns
+    are reserved using "reserved" statements.
+
+CONVERTERS
+    The functions converting between entity structs of "gf gen dao" and the golang messages of generated proto files
+    are generated if option "converter" is given, like "UserToPb" and "UserFromPb" for table "user".
+    The time, json, decimal and nullable columns are converted according to the type mapping of both sides,
+    in which the options "stdTime" and "gJsonSupport" should be the same as the ones of "gf gen dao".
+
+SERVICES
+    The CRUD service proto files are generated if option "service" is given, like "UserService" for table "user",
+    which has rpc "Get", "List", "Create", "Update" and "Delete" and their request and response messages.
+    The "List" is paginated and filtered by the indexed columns, and the "Get", "Update" and "Delete"
+    are generated only if the table has primary key. The filter fields are prefixed with "filter",
+    like "filter_page", if the column names clash with the fields "page", "size", "list", "total" and "entity".
+    The entity proto files are imported using paths relative to option "protoRoot".
+
+WELL-KNOWN TYPES
+    The well-known types are used for columns with options below, and the proto files are imported automatically:
+    wktTime      datetime/timestamp to google.protobuf.Timestamp, date to google.type.Date,
+                 time to google.type.TimeOfDay
+    wktNullable  nullable columns of scalar types to wrappers, like google.protobuf.Int64Value
+    wktJson      json columns to google.protobuf.Struct
+    The google.type protos are not shipped with protoc, which are from https://github.com/googleapis/googleapis.
 `
 	cGenPbEntityBriefPath         = `directory path for generated files`
 	cGenPbEntityBriefPackage      = `package name for all entity proto files`
 	cGenPbEntityBriefLink         = `database configuration, the same as the ORM configuration of GoFrame`
-	cGenPbEntityBriefTables       = `generate models only for given tables, multiple table names separated with ','`
+	cGenPbEntityBriefTables       = `generate models only for given tables, multiple table names separated with ',', wildcard '*' is supported`
+	cGenPbEntityBriefTablesEx     = `generate models excluding given tables, multiple table names separated with ',', wildcard '*' is supported`
 	cGenPbEntityBriefPrefix       = `add specified prefix for all entity names and entity proto files`
 	cGenPbEntityBriefRemovePrefix = `remove specified prefix of the table, multiple prefix separated with ','`
 	cGenPbEntityBriefOption       = `extra protobuf options`
+	cGenPbEntityBriefStyle        = `style of generated proto files, "gogo" or "plain", default "gogo"`
+	cGenPbEntityBriefConverter    = `directory path for generated converters between entity structs and messages, it generates no converter if it is empty`
+	cGenPbEntityBriefEntityImport = `import path of entity package for converters, default is "internal/model/entity" of module in go.mod`
+	cGenPbEntityBriefStdTime      = `entity uses time.Time for time columns, which should be the same as "stdTime" of "gf gen dao"`
+	cGenPbEntityBriefGJsonSupport = `entity uses *gjson.Json for json columns, which should be the same as "gJsonSupport" of "gf gen dao"`
+	cGenPbEntityBriefService      = `directory path for generated CRUD service proto files, it generates no service if it is empty`
+	cGenPbEntityBriefProtoRoot    = `root directory of proto import paths, which is the "-I" option of protoc, default is current working directory`
+	cGenPbEntityBriefWktTime      = `use google.protobuf.Timestamp, google.type.Date and google.type.TimeOfDay for time columns instead of int64`
+	cGenPbEntityBriefWktNullable  = `use wrapper types like google.protobuf.Int64Value for nullable columns of scalar types`
+	cGenPbEntityBriefWktJson      = `use google.protobuf.Struct for json columns instead of string`
+	cGenPbEntityBriefForce        = `overwrite the generated files even if they were modified after last generating`
 	cGenPbEntityBriefGroup        = `
 specifying the configuration group name of database for generated ORM instance,
 it's not necessary and the default value is "default"
@@ -83,6 +132,40 @@ set it to "none" to ignore json tag generating.
 `
 )
 
+const (
+	pbEntityStyleGogo  = "gogo"
+	pbEntityStylePlain = "plain"
+)
+
+const (
+	pbTypeTimestamp = "google.protobuf.Timestamp"
+	pbTypeDate      = "google.type.Date"
+	pbTypeTimeOfDay = "google.type.TimeOfDay"
+	pbTypeStruct    = "google.protobuf.Struct"
+)
+
+// pbWrapperTypes is the mapping from scalar types to their wrapper types for nullable columns.
+var pbWrapperTypes = map[string]string{
+	"double": "google.protobuf.DoubleValue",
+	"float":  "google.protobuf.FloatValue",
+	"int64":  "google.protobuf.Int64Value",
+	"uint64": "google.protobuf.UInt64Value",
+	"int32":  "google.protobuf.Int32Value",
+	"uint32": "google.protobuf.UInt32Value",
+	"bool":   "google.protobuf.BoolValue",
+	"string": "google.protobuf.StringValue",
+	"bytes":  "google.protobuf.BytesValue",
+}
+
+// pbWellKnownTypeImports is the patterns of well-known type names and their proto files to import.
+var pbWellKnownTypeImports = [][2]string{
+	{`google\.protobuf\.Timestamp\s`, "google/protobuf/timestamp.proto"},
+	{`google\.protobuf\.Struct\s`, "google/protobuf/struct.proto"},
+	{`google\.protobuf\.\w+Value\s`, "google/protobuf/wrappers.proto"},
+	{`google\.type\.Date\s`, "google/type/date.proto"},
+	{`google\.type\.TimeOfDay\s`, "google/type/timeofday.proto"},
+}
+
 type (
 	cGenPbEntityInput struct {
 		g.Meta       `name:"pbentity" config:"{cGenPbEntityConfig}" brief:"{cGenPbEntityBrief}" eg:"{cGenPbEntityEg}" ad:"{cGenPbEntityAd}"`
@@ -90,11 +173,24 @@ type (
 		Package      string `name:"package"      short:"k" brief:"{cGenPbEntityBriefPackage}"`
 		Link         string `name:"link"         short:"l" brief:"{cGenPbEntityBriefLink}"`
 		Tables       string `name:"tables"       short:"t" brief:"{cGenPbEntityBriefTables}"`
+		TablesEx     string `name:"tablesEx"     short:"e" brief:"{cGenPbEntityBriefTablesEx}"`
+		Group        string `name:"group"        short:"g" brief:"{cGenPbEntityBriefGroup}" d:"default"`
 		Prefix       string `name:"prefix"       short:"f" brief:"{cGenPbEntityBriefPrefix}"`
 		RemovePrefix string `name:"removePrefix" short:"r" brief:"{cGenPbEntityBriefRemovePrefix}"`
 		NameCase     string `name:"nameCase"     short:"n" brief:"{cGenPbEntityBriefNameCase}" d:"Camel"`
 		JsonCase     string `name:"jsonCase"     short:"j" brief:"{cGenPbEntityBriefJsonCase}" d:"CamelLower"`
 		Option       string `name:"option"       short:"o" brief:"{cGenPbEntityBriefOption}"`
+		Style        string `name:"style"        short:"s" brief:"{cGenPbEntityBriefStyle}" d:"gogo"`
+		Converter    string `name:"converter"    short:"c" brief:"{cGenPbEntityBriefConverter}"`
+		EntityImport string `name:"entityImport" short:"i" brief:"{cGenPbEntityBriefEntityImport}"`
+		StdTime      bool   `name:"stdTime"                brief:"{cGenPbEntityBriefStdTime}" orphan:"true"`
+		GJsonSupport bool   `name:"gJsonSupport"           brief:"{cGenPbEntityBriefGJsonSupport}" orphan:"true"`
+		Service      string `name:"service"                brief:"{cGenPbEntityBriefService}"`
+		ProtoRoot    string `name:"protoRoot"              brief:"{cGenPbEntityBriefProtoRoot}" d:"."`
+		WktTime      bool   `name:"wktTime"                brief:"{cGenPbEntityBriefWktTime}" orphan:"true"`
+		WktNullable  bool   `name:"wktNullable"            brief:"{cGenPbEntityBriefWktNullable}" orphan:"true"`
+		WktJson      bool   `name:"wktJson"                brief:"{cGenPbEntityBriefWktJson}" orphan:"true"`
+		Force        bool   `name:"force"                  brief:"{cGenPbEntityBriefForce}" orphan:"true"`
 	}
 	cGenPbEntityOutput struct{}
 
@@ -102,6 +198,7 @@ type (
 		cGenPbEntityInput
 		TableName    string // TableName specifies the table name of the table.
 		NewTableName string // NewTableName specifies the prefix-stripped name of the table.
+		DbType       string // DbType specifies the database type of the link, which is used for type mapping.
 	}
 )
 
@@ -115,98 +212,144 @@ func init() {
 		`cGenPbEntityBriefPackage`:      cGenPbEntityBriefPackage,
 		`cGenPbEntityBriefLink`:         cGenPbEntityBriefLink,
 		`cGenPbEntityBriefTables`:       cGenPbEntityBriefTables,
+		`cGenPbEntityBriefTablesEx`:     cGenPbEntityBriefTablesEx,
 		`cGenPbEntityBriefPrefix`:       cGenPbEntityBriefPrefix,
 		`cGenPbEntityBriefRemovePrefix`: cGenPbEntityBriefRemovePrefix,
 		`cGenPbEntityBriefGroup`:        cGenPbEntityBriefGroup,
 		`cGenPbEntityBriefNameCase`:     cGenPbEntityBriefNameCase,
 		`cGenPbEntityBriefJsonCase`:     cGenPbEntityBriefJsonCase,
 		`cGenPbEntityBriefOption`:       cGenPbEntityBriefOption,
+		`cGenPbEntityBriefStyle`:        cGenPbEntityBriefStyle,
+		`cGenPbEntityBriefConverter`:    cGenPbEntityBriefConverter,
+		`cGenPbEntityBriefEntityImport`: cGenPbEntityBriefEntityImport,
+		`cGenPbEntityBriefStdTime`:      cGenPbEntityBriefStdTime,
+		`cGenPbEntityBriefGJsonSupport`: cGenPbEntityBriefGJsonSupport,
+		`cGenPbEntityBriefService`:      cGenPbEntityBriefService,
+		`cGenPbEntityBriefProtoRoot`:    cGenPbEntityBriefProtoRoot,
+		`cGenPbEntityBriefWktTime`:      cGenPbEntityBriefWktTime,
+		`cGenPbEntityBriefWktNullable`:  cGenPbEntityBriefWktNullable,
+		`cGenPbEntityBriefWktJson`:      cGenPbEntityBriefWktJson,
+		`cGenPbEntityBriefForce`:        cGenPbEntityBriefForce,
 	})
 }
 
 func (c cGen) PbEntity(ctx context.Context, in cGenPbEntityInput) (out *cGenPbEntityOutput, err error) {
 	var (
-		config = g.Cfg()
+		config  = g.Cfg()
+		entries = []string{"command line options"}
+		inputs  = []cGenPbEntityInput{in}
 	)
 	if config.Available(ctx) {
 		v := config.MustGet(ctx, cGenPbEntityConfig)
 		if v.IsSlice() {
+			entries, inputs = nil, nil
 			for i := 0; i < len(v.Interfaces()); i++ {
-				doGenPbEntityForArray(ctx, i, in)
+				item := in
+				entries = append(entries, fmt.Sprintf(`configuration "%s.%d"`, cGenPbEntityConfig, i))
+				if err = config.MustGet(ctx, fmt.Sprintf(`%s.%d`, cGenPbEntityConfig, i)).Scan(&item); err != nil {
+					mlog.Fatalf(`invalid %s: %+v`, entries[i], err)
+				}
+				inputs = append(inputs, item)
 			}
-		} else {
-			doGenPbEntityForArray(ctx, -1, in)
+		} else if !v.IsNil() {
+			entries = []string{fmt.Sprintf(`configuration "%s"`, cGenPbEntityConfig)}
+		}
+	}
+	// All the entries are validated before generating any of them.
+	for i, item := range inputs {
+		if err = validatePbEntityInput(item); err != nil {
+			mlog.Fatalf(`invalid %s: %v`, entries[i], err)
 		}
-	} else {
-		doGenPbEntityForArray(ctx, -1, in)
+	}
+	for i, item := range inputs {
+		doGenPbEntityForArray(ctx, entries[i], item)
 	}
 	mlog.Print("done!")
 	return
 }
 
-func doGenPbEntityForArray(ctx context.Context, index int, in cGenPbEntityInput) {
+// doGenPbEntityForArray implements the "gen pbentity" command for configuration entry `entry`.
+func doGenPbEntityForArray(ctx context.Context, entry string, in cGenPbEntityInput) {
 	var (
 		err error
 		db  gdb.DB
 	)
-	if index >= 0 {
-		err = g.Cfg().MustGet(
-			ctx,
-			fmt.Sprintf(`%s.%d`, cGenDaoConfig, index),
-		).Scan(&in)
-		if err != nil {
-			mlog.Fatalf(`invalid configuration of "%s": %+v`, cGenDaoConfig, err)
-		}
-	}
-	if in.Package == "" {
-		mlog.Fatal("package name should not be empty")
-	}
 	removePrefixArray := gstr.SplitAndTrim(in.RemovePrefix, ",")
 	// It uses user passed database configuration.
-	if in.Link != "" {
-		var (
-			tempGroup = gtime.TimestampNanoStr()
-			match, _  = gregex.MatchString(`([a-z]+):(.+)`, in.Link)
-		)
-		if len(match) == 3 {
-			gdb.AddConfigNode(tempGroup, gdb.ConfigNode{
-				Type: gstr.Trim(match[1]),
-				Link: gstr.Trim(match[2]),
-			})
-			db, _ = gdb.Instance(tempGroup)
-		}
-	} else {
-		db = g.DB()
-	}
+	db = getDatabase(in.Link, in.Group)
 	if db == nil {
-		mlog.Fatal("database initialization failed")
+		mlog.Fatalf(`database initialization failed for %s`, entry)
 	}
 
-	tableNames := ([]string)(nil)
-	if in.Tables != "" {
-		tableNames = gstr.SplitAndTrim(in.Tables, ",")
-	} else {
-		tableNames, err = db.Tables(context.TODO())
-		if err != nil {
-			mlog.Fatalf("fetching tables failed: \n %v", err)
-		}
+	tableNames, err := db.Tables(ctx)
+	if err != nil {
+		mlog.Fatalf("fetching tables failed for %s: \n %v", entry, err)
 	}
+	tableNames = filterTableNames(tableNames, in.Tables, in.TablesEx)
+	if len(tableNames) == 0 {
+		mlog.Fatalf("no table matches the given table patterns of %s", entry)
+	}
+
+	// All the generated files are recorded in the manifest,
+	// it is a full generating if no tables are specified.
+	beginGeneratedManifest(getGeneratedManifestScope("gen pbentity", in.Path, in.Link, in.Group))
+	defer endGeneratedManifest(in.Tables == "")
 
+	converterHelpers := make([]string, 0)
 	for _, tableName := range tableNames {
 		newTableName := tableName
 		for _, v := range removePrefixArray {
 			newTableName = gstr.TrimLeftStr(newTableName, v, 1)
 		}
-		generatePbEntityContentFile(ctx, db, cGenPbEntityInternalInput{
+		helpers := generatePbEntityContentFile(ctx, db, cGenPbEntityInternalInput{
 			cGenPbEntityInput: in,
 			TableName:         tableName,
 			NewTableName:      newTableName,
+			DbType:            db.GetConfig().Type,
 		})
+		converterHelpers = append(converterHelpers, helpers...)
+	}
+	if in.Converter != "" {
+		generatePbEntityConverterHelperFile(converterHelpers, in)
+	}
+}
+
+// validatePbEntityInput checks the options of "gen pbentity",
+// the returned error describes which option is invalid.
+func validatePbEntityInput(in cGenPbEntityInput) error {
+	if in.Package == "" {
+		return gerror.New(`option "package" should not be empty`)
+	}
+	switch in.Style {
+	case pbEntityStyleGogo, pbEntityStylePlain:
+	default:
+		return gerror.Newf(
+			`invalid style "%s" of option "style", it should be "%s" or "%s"`,
+			in.Style, pbEntityStyleGogo, pbEntityStylePlain,
+		)
+	}
+	if !isValidPbEntityCase(in.NameCase) || in.NameCase == "none" {
+		return gerror.Newf(`invalid case "%s" of option "nameCase"`, in.NameCase)
+	}
+	if !isValidPbEntityCase(in.JsonCase) {
+		return gerror.Newf(`invalid case "%s" of option "jsonCase"`, in.JsonCase)
+	}
+	return nil
+}
+
+// isValidPbEntityCase checks whether `caseStr` is supported by formatCase.
+func isValidPbEntityCase(caseStr string) bool {
+	switch gstr.ToLower(caseStr) {
+	case "camel", "camellower", "kebab", "kebabscreaming",
+		"snake", "snakefirstupper", "snakescreaming", "none", "":
+		return true
 	}
+	return false
 }
 
-// generatePbEntityContentFile generates the protobuf files for given table.
-func generatePbEntityContentFile(ctx context.Context, db gdb.DB, in cGenPbEntityInternalInput) {
+// generatePbEntityContentFile generates the protobuf files for given table,
+// it also generates the converter if option "converter" is given, and returns the converter helpers used.
+func generatePbEntityContentFile(ctx context.Context, db gdb.DB, in cGenPbEntityInternalInput) []string {
 	fieldMap, err := db.TableFields(ctx, in.TableName)
 	if err != nil {
 		mlog.Fatalf("fetching tables fields failed for table '%s':\n%v", in.TableName, err)
@@ -216,31 +359,52 @@ func generatePbEntityContentFile(ctx context.Context, db gdb.DB, in cGenPbEntity
 	var (
 		tableNameCamelCase  = gstr.CaseCamel(newTableName)
 		tableNameSnakeCase  = gstr.CaseSnake(newTableName)
-		entityMessageDefine = generateEntityMessageDefinition(tableNameCamelCase, fieldMap, in)
 		fileName            = gstr.Trim(tableNameSnakeCase, "-_.")
 		path                = gfile.Join(in.Path, fileName+".proto")
+		fieldNumbers        = loadPbEntityFieldNumbers(path, tableNameCamelCase)
+		entityMessageDefine = generateEntityMessageDefinition(tableNameCamelCase, fieldMap, fieldNumbers, in)
 	)
 	entityContent := gstr.ReplaceByMap(getTplPbEntityContent(""), g.MapStrStr{
 		"{PackageName}":   in.Package,
-		"{OptionContent}": in.Option,
+		"{ImportContent}": getPbEntityImports(entityMessageDefine, in),
+		"{OptionContent}": getPbEntityOptions(in),
 		"{EntityMessage}": entityMessageDefine,
 	})
-	if err := gfile.PutContents(path, strings.TrimSpace(entityContent)); err != nil {
+	if err := putGeneratedContents(path, strings.TrimSpace(entityContent), in.Force); err != nil {
 		mlog.Fatalf("writing content to '%s' failed: %v", path, err)
 	} else {
 		mlog.Print("generated:", path)
 	}
+	if in.Service != "" {
+		table, err := loadTableSchema(ctx, db, in.TableName)
+		if err != nil {
+			mlog.Fatalf("fetching schema failed for table '%s':\n%v", in.TableName, err)
+		}
+		generatePbEntityServiceFile(path, tableNameCamelCase, fieldMap, table, in)
+	}
+	if in.Converter == "" {
+		return nil
+	}
+	return generatePbEntityConverterFile(tableNameCamelCase, fieldMap, in)
 }
 
 // generateEntityMessageDefinition generates and returns the message definition for specified table.
-func generateEntityMessageDefinition(entityName string, fieldMap map[string]*gdb.TableField, in cGenPbEntityInternalInput) string {
+// The field numbers of previous generating are kept in `fieldNumbers`, and the removed fields are reserved.
+func generateEntityMessageDefinition(
+	entityName string, fieldMap map[string]*gdb.TableField, fieldNumbers *pbEntityFieldNumbers, in cGenPbEntityInternalInput,
+) string {
 	var (
-		buffer = bytes.NewBuffer(nil)
-		array  = make([][]string, len(fieldMap))
-		names  = sortFieldKeyForPbEntity(fieldMap)
+		buffer     = bytes.NewBuffer(nil)
+		array      = make([][]string, len(fieldMap))
+		names      = sortFieldKeyForPbEntity(fieldMap)
+		fieldNames = make([]string, len(names))
 	)
 	for index, name := range names {
-		array[index] = generateMessageFieldForPbEntity(index+1, fieldMap[name], in)
+		fieldNames[index] = formatCase(name, in.NameCase)
+	}
+	numbers := fieldNumbers.assign(fieldNames)
+	for index, name := range names {
+		array[index] = generateMessageFieldForPbEntity(numbers[index], fieldMap[name], in)
 	}
 	tw := tablewriter.NewWriter(buffer)
 	tw.SetBorder(false)
@@ -254,79 +418,129 @@ func generateEntityMessageDefinition(entityName string, fieldMap map[string]*gdb
 	stContent = gstr.Replace(stContent, "  #", "")
 	buffer.Reset()
 	buffer.WriteString(fmt.Sprintf("message %s {\n", entityName))
+	buffer.WriteString(fieldNumbers.reservedDefinition())
 	buffer.WriteString(stContent)
 	buffer.WriteString("}")
 	return buffer.String()
 }
 
-// generateMessageFieldForPbEntity generates and returns the message definition for specified field.
-func generateMessageFieldForPbEntity(index int, field *gdb.TableField, in cGenPbEntityInternalInput) []string {
+// getPbEntityFieldType returns the protobuf type name of specified field.
+func getPbEntityFieldType(field *gdb.TableField, in cGenPbEntityInternalInput) string {
 	var (
-		typeName   string
-		comment    string
-		jsonTagStr string
+		typeName string
+		timeType string // timeType is the database type of time column.
+		isJson   bool   // isJson specifies whether it is json column.
 	)
 	t, _ := gregex.ReplaceString(`\(.+\)`, "", field.Type)
 	t = gstr.Split(gstr.Trim(t), " ")[0]
 	t = gstr.ToLower(t)
-	switch t {
-	case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
-		typeName = "bytes"
-
-	case "bit", "int", "tinyint", "small_int", "smallint", "medium_int", "mediumint", "serial":
-		if gstr.ContainsI(field.Type, "unsigned") {
-			typeName = "uint32"
-		} else {
-			typeName = "int32"
+	// Dialect specific type mapping takes priority of the common one.
+	if typeName = getDialectPbType(in.DbType, field); typeName != "" {
+		switch getDialectFieldType(in.DbType, field) {
+		case dialectTypeTime:
+			timeType = t
+		case dialectTypeJson:
+			isJson = true
 		}
+	} else {
+		switch t {
+		case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
+			typeName = "bytes"
 
-	case "int8", "big_int", "bigint", "bigserial":
-		if gstr.ContainsI(field.Type, "unsigned") {
-			typeName = "uint64"
-		} else {
-			typeName = "int64"
-		}
-
-	case "real":
-		typeName = "float"
-
-	case "float", "double", "decimal", "smallmoney":
-		typeName = "double"
+		case "bit", "int", "tinyint", "small_int", "smallint", "medium_int", "mediumint", "serial":
+			if gstr.ContainsI(field.Type, "unsigned") {
+				typeName = "uint32"
+			} else {
+				typeName = "int32"
+			}
 
-	case "bool":
-		typeName = "bool"
+		case "int8", "big_int", "bigint", "bigserial":
+			if gstr.ContainsI(field.Type, "unsigned") {
+				typeName = "uint64"
+			} else {
+				typeName = "int64"
+			}
 
-	case "datetime", "timestamp", "date", "time":
-		typeName = "int64"
+		case "real":
+			typeName = "float"
 
-	default:
-		// Auto detecting type.
-		switch {
-		case strings.Contains(t, "int"):
-			typeName = "int"
-		case strings.Contains(t, "text") || strings.Contains(t, "char"):
-			typeName = "string"
-		case strings.Contains(t, "float") || strings.Contains(t, "double"):
+		case "float", "double", "decimal", "smallmoney":
 			typeName = "double"
-		case strings.Contains(t, "bool"):
+
+		case "bool":
 			typeName = "bool"
-		case strings.Contains(t, "binary") || strings.Contains(t, "blob"):
-			typeName = "bytes"
-		case strings.Contains(t, "date") || strings.Contains(t, "time"):
+
+		case "datetime", "timestamp", "date", "time":
 			typeName = "int64"
-		default:
+			timeType = t
+
+		case "json", "jsonb":
 			typeName = "string"
+			isJson = true
+
+		default:
+			// Auto detecting type.
+			switch {
+			case strings.Contains(t, "int"):
+				typeName = "int32"
+			case strings.Contains(t, "text") || strings.Contains(t, "char"):
+				typeName = "string"
+			case strings.Contains(t, "float") || strings.Contains(t, "double"):
+				typeName = "double"
+			case strings.Contains(t, "bool"):
+				typeName = "bool"
+			case strings.Contains(t, "binary") || strings.Contains(t, "blob"):
+				typeName = "bytes"
+			case strings.Contains(t, "date") || strings.Contains(t, "time"):
+				typeName = "int64"
+				timeType = t
+			default:
+				typeName = "string"
+			}
 		}
 	}
-	comment = gstr.ReplaceByArray(field.Comment, g.SliceStr{
-		"\n", " ",
-		"\r", " ",
-	})
-	comment = gstr.Trim(comment)
-	comment = gstr.Replace(comment, `\n`, " ")
+	// Well-known types mapping.
+	switch {
+	case timeType != "" && in.WktTime:
+		typeName = getPbEntityTimeType(timeType)
+	case isJson && in.WktJson:
+		typeName = pbTypeStruct
+	case field.Null && in.WktNullable && pbWrapperTypes[typeName] != "":
+		typeName = pbWrapperTypes[typeName]
+	}
+	return typeName
+}
+
+// generateMessageFieldForPbEntity generates and returns the message definition for specified field.
+func generateMessageFieldForPbEntity(index int, field *gdb.TableField, in cGenPbEntityInternalInput) []string {
+	var (
+		typeName   = getPbEntityFieldType(field, in)
+		comment    string
+		jsonTagStr string
+		directives = parseFieldDirectives(field.Comment)
+		options    = make([]string, 0)
+	)
+	comment = formatComment(field.Comment)
 	comment, _ = gregex.ReplaceString(`\s{2,}`, ` `, comment)
-	if jsonTagName := formatCase(field.Name, in.JsonCase); jsonTagName != "" {
-		jsonTagStr = fmt.Sprintf(`[(gogoproto.jsontag) = "%s"]`, jsonTagName)
+	if len(directives.Enum) > 0 {
+		comment = gstr.Trim(fmt.Sprintf(`%s Enum: %s.`, comment, gstr.Join(directives.Enum, ", ")))
+	}
+	if jsonTagName := formatCase(field.Name, in.JsonCase); in.Style == pbEntityStylePlain {
+		// The json name cannot be ignored or omitted, which is only renamed in plain style.
+		if jsonTagName != "" && !directives.JsonIgnore {
+			if directives.JsonName != "" {
+				jsonTagName = directives.JsonName
+			}
+			options = append(options, fmt.Sprintf(`json_name = "%s"`, jsonTagName))
+		}
+	} else if jsonTagName != "" || directives.JsonIgnore {
+		options = append(options, fmt.Sprintf(`(gogoproto.jsontag) = "%s"`, directives.JsonTag(jsonTagName)))
+	}
+	if directives.Deprecated {
+		options = append(options, `deprecated = true`)
+	}
+	if len(options) > 0 {
+		jsonTagStr = fmt.Sprintf(`[%s]`, gstr.Join(options, ", "))
 		// beautiful indent.
 		if index < 10 {
 			// 3 spaces
@@ -347,6 +561,70 @@ func generateMessageFieldForPbEntity(index int, field *gdb.TableField, in cGenPb
 	}
 }
 
+// getPbEntityTimeType returns the well-known type of time column in database type `timeType`.
+func getPbEntityTimeType(timeType string) string {
+	switch {
+	case timeType == "date":
+		return pbTypeDate
+	case gstr.HasPrefix(timeType, "time") && !gstr.HasPrefix(timeType, "timestamp"):
+		return pbTypeTimeOfDay
+	default:
+		return pbTypeTimestamp
+	}
+}
+
+// getPbEntityImports returns the import lines of proto files that are used by message definition `message`.
+func getPbEntityImports(message string, in cGenPbEntityInternalInput) string {
+	imports := make([]string, 0)
+	if in.Style != pbEntityStylePlain {
+		imports = append(imports, `import "github.com/gogo/protobuf/gogoproto/gogo.proto";`)
+	}
+	for _, item := range pbWellKnownTypeImports {
+		if gregex.IsMatchString(item[0], message) {
+			imports = append(imports, fmt.Sprintf(`import "%s";`, item[1]))
+		}
+	}
+	return gstr.Join(imports, "\n")
+}
+
+// getPbEntityOptions returns the file options of proto file,
+// the "go_package" option is computed from the module of go.mod in plain style if it is not given.
+func getPbEntityOptions(in cGenPbEntityInternalInput) string {
+	if in.Style != pbEntityStylePlain || gstr.Contains(in.Option, "go_package") {
+		return in.Option
+	}
+	goPackage := getPbEntityGoPackage(in.Path)
+	if goPackage == "" {
+		mlog.Printf(`go.mod is not found for path "%s", option "go_package" is not generated`, in.Path)
+		return in.Option
+	}
+	return gstr.Trim(fmt.Sprintf("option go_package = \"%s\";\n%s", goPackage, in.Option))
+}
+
+// getPbEntityGoPackage returns the go import path of directory `path`,
+// which is computed from the nearest go.mod in `path` and its parent directories.
+func getPbEntityGoPackage(path string) string {
+	var (
+		dirPath = gfile.Abs(path)
+		subPath = ""
+	)
+	for {
+		if goModPath := gfile.Join(dirPath, "go.mod"); gfile.Exists(goModPath) {
+			match, _ := gregex.MatchString(`(?m)^module\s+(\S+)`, gfile.GetContents(goModPath))
+			if len(match) < 2 {
+				return ""
+			}
+			return gstr.TrimRight(match[1]+"/"+subPath, "/")
+		}
+		parentPath := gfile.Dir(dirPath)
+		if parentPath == dirPath {
+			return ""
+		}
+		subPath = gstr.TrimRight(gfile.Basename(dirPath)+"/"+subPath, "/")
+		dirPath = parentPath
+	}
+}
+
 func getTplPbEntityContent(tplEntityPath string) string {
 	if tplEntityPath != "" {
 		return gfile.GetContents(tplEntityPath)
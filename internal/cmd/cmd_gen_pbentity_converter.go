@@ -0,0 +1,504 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf-cli/v2/internal/consts"
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+)
+
+const (
+	// pbEntityConverterHelperFile is the file name of helper functions shared by all converters.
+	pbEntityConverterHelperFile = `pbentity.go`
+
+	tplVarPackageName   = `{TplPackageName}`
+	tplVarEntityName    = `{TplEntityName}`
+	tplVarMessageName   = `{TplMessageName}`
+	tplVarToPbFields    = `{TplToPbFields}`
+	tplVarFromPbFields  = `{TplFromPbFields}`
+	tplVarHelpers       = `{TplHelpers}`
+	pbEntityGoTypeGTime = `*gtime.Time`
+	pbEntityGoTypeTime  = `time.Time`
+	pbEntityGoTypeGJson = `*gjson.Json`
+)
+
+// pbEntityGoTypes maps the protobuf scalar types to golang types.
+var pbEntityGoTypes = map[string]string{
+	"double": "float64",
+	"float":  "float32",
+	"int64":  "int64",
+	"uint64": "uint64",
+	"int32":  "int32",
+	"uint32": "uint32",
+	"bool":   "bool",
+	"string": "string",
+	"bytes":  "[]byte",
+}
+
+// pbEntityConverterHelpers are the helper functions for converters, which are generated only if they are used.
+var pbEntityConverterHelpers = map[string]string{
+	"pbGTimeToTime": `
+func pbGTimeToTime(t *gtime.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time
+}`,
+	"pbTimeToGTime": `
+func pbTimeToGTime(t time.Time) *gtime.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return gtime.NewFromTime(t)
+}`,
+	"pbTimeToInt64": `
+func pbTimeToInt64(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}`,
+	"pbInt64ToTime": `
+func pbInt64ToTime(v int64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(v, 0)
+}`,
+	"pbTimeToDate": `
+func pbTimeToDate(t time.Time) *date.Date {
+	if t.IsZero() {
+		return nil
+	}
+	return &date.Date{Year: int32(t.Year()), Month: int32(t.Month()), Day: int32(t.Day())}
+}`,
+	"pbDateToTime": `
+func pbDateToTime(d *date.Date) time.Time {
+	if d == nil {
+		return time.Time{}
+	}
+	return time.Date(int(d.Year), time.Month(d.Month), int(d.Day), 0, 0, 0, 0, time.Local)
+}`,
+	"pbTimeToTimeOfDay": `
+func pbTimeToTimeOfDay(t time.Time) *timeofday.TimeOfDay {
+	if t.IsZero() {
+		return nil
+	}
+	return &timeofday.TimeOfDay{
+		Hours:   int32(t.Hour()),
+		Minutes: int32(t.Minute()),
+		Seconds: int32(t.Second()),
+		Nanos:   int32(t.Nanosecond()),
+	}
+}`,
+	"pbTimeOfDayToTime": `
+func pbTimeOfDayToTime(t *timeofday.TimeOfDay) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return time.Date(0, 1, 1, int(t.Hours), int(t.Minutes), int(t.Seconds), int(t.Nanos), time.Local)
+}`,
+	"pbGJsonToString": `
+func pbGJsonToString(j *gjson.Json) string {
+	if j == nil {
+		return ""
+	}
+	return j.MustToJsonString()
+}`,
+	"pbStringToGJson": `
+func pbStringToGJson(s string) *gjson.Json {
+	if s == "" {
+		return nil
+	}
+	return gjson.New(s)
+}`,
+	"pbTimeToTimestamp": `
+func pbTimeToTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}`,
+	"pbTimestampToTime": `
+func pbTimestampToTime(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime().Local()
+}`,
+	"pbStringToStruct": `
+func pbStringToStruct(s string) *structpb.Struct {
+	if s == "" {
+		return nil
+	}
+	v := &structpb.Struct{}
+	if err := v.UnmarshalJSON([]byte(s)); err != nil {
+		return nil
+	}
+	return v
+}`,
+	"pbStructToString": `
+func pbStructToString(v *structpb.Struct) string {
+	if v == nil {
+		return ""
+	}
+	b, err := v.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}`,
+}
+
+// pbEntityConverterGogoHelpers overwrites the helper functions of well-known types in gogo style.
+var pbEntityConverterGogoHelpers = map[string]string{
+	"pbTimeToTimestamp": `
+func pbTimeToTimestamp(t time.Time) *types.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	v, err := types.TimestampProto(t)
+	if err != nil {
+		return nil
+	}
+	return v
+}`,
+	"pbTimestampToTime": `
+func pbTimestampToTime(t *types.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	v, err := types.TimestampFromProto(t)
+	if err != nil {
+		return time.Time{}
+	}
+	return v.Local()
+}`,
+	"pbStringToStruct": `
+func pbStringToStruct(s string) *types.Struct {
+	if s == "" {
+		return nil
+	}
+	v := &types.Struct{}
+	if err := jsonpb.UnmarshalString(s, v); err != nil {
+		return nil
+	}
+	return v
+}`,
+	"pbStructToString": `
+func pbStructToString(v *types.Struct) string {
+	if v == nil {
+		return ""
+	}
+	s, err := (&jsonpb.Marshaler{}).MarshalToString(v)
+	if err != nil {
+		return ""
+	}
+	return s
+}`,
+}
+
+// pbEntityConversion is the conversion expressions of a field between entity and message,
+// in which the "%s" is the field of source struct.
+type pbEntityConversion struct {
+	ToPb    string   // ToPb converts entity field to message field.
+	FromPb  string   // FromPb converts message field to entity field, empty if it is not supported.
+	Helpers []string // Helpers are the names of helper functions used.
+}
+
+// generatePbEntityConverterFile generates the converter file between the entity struct and the message of table,
+// it returns the names of helper functions used by converter.
+func generatePbEntityConverterFile(
+	messageName string, fieldMap map[string]*gdb.TableField, in cGenPbEntityInternalInput,
+) []string {
+	var (
+		entityName   = gstr.CaseCamel(in.NewTableName)
+		path         = gfile.Join(in.Converter, gstr.CaseSnake(in.NewTableName)+".go")
+		toPbFields   = make([]string, 0)
+		fromPbFields = make([]string, 0)
+		helpers      = make([]string, 0)
+		daoInput     = generateStructDefinitionInput{
+			cGenDaoInternalInput: cGenDaoInternalInput{
+				cGenDaoInput: cGenDaoInput{StdTime: in.StdTime, GJsonSupport: in.GJsonSupport},
+				DbType:       in.DbType,
+			},
+		}
+	)
+	for _, name := range sortFieldKeyForPbEntity(fieldMap) {
+		var (
+			field           = fieldMap[name]
+			entityFieldName = gstr.CaseCamel(field.Name)
+			pbFieldName     = getPbGoName(formatCase(field.Name, in.NameCase))
+			entityType      = gstr.Trim(generateStructFieldDefinition(field, daoInput)[1], " #")
+			pbType          = getPbEntityFieldType(field, in)
+			conversion      = getPbEntityConversion(entityType, pbType, in)
+		)
+		toPbFields = append(toPbFields, fmt.Sprintf(
+			"%s: %s,", pbFieldName, fmt.Sprintf(conversion.ToPb, "in."+entityFieldName),
+		))
+		if conversion.FromPb != "" {
+			fromPbFields = append(fromPbFields, fmt.Sprintf(
+				"%s: %s,", entityFieldName, fmt.Sprintf(conversion.FromPb, "in."+pbFieldName),
+			))
+		} else {
+			fromPbFields = append(fromPbFields, fmt.Sprintf(
+				"// %s: conversion from %s to %s is not supported.", entityFieldName, pbType, entityType,
+			))
+		}
+		helpers = append(helpers, conversion.Helpers...)
+	}
+	content := gstr.ReplaceByMap(consts.TemplatePbEntityConverterContent, g.MapStrStr{
+		tplVarPackageName:  gfile.Basename(in.Converter),
+		tplVarEntityName:   entityName,
+		tplVarMessageName:  getPbGoName(messageName),
+		tplVarToPbFields:   gstr.Join(toPbFields, "\n"),
+		tplVarFromPbFields: gstr.Join(fromPbFields, "\n"),
+	})
+	content = gstr.Replace(content, tplVarPackageImports, getPbEntityConverterImports(content, in))
+	if err := putGeneratedContents(path, strings.TrimSpace(content), in.Force); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+	} else {
+		mlog.Print("generated:", path)
+	}
+	return helpers
+}
+
+// generatePbEntityConverterHelperFile generates the helper functions `helpers` shared by converters.
+// The helpers of existing file are kept if it is not a full generating, as they may be used by other converters.
+func generatePbEntityConverterHelperFile(helpers []string, in cGenPbEntityInput) {
+	var (
+		path     = gfile.Join(in.Converter, pbEntityConverterHelperFile)
+		names    = make(map[string]bool)
+		contents = make([]string, 0)
+	)
+	if in.Tables != "" && gfile.Exists(path) {
+		match, _ := gregex.MatchAllString(`(?m)^func (pb\w+)\(`, gfile.GetContents(path))
+		for _, v := range match {
+			names[v[1]] = true
+		}
+	}
+	for _, name := range helpers {
+		names[name] = true
+	}
+	if len(names) == 0 {
+		return
+	}
+	for name := range names {
+		if in.Style != pbEntityStylePlain && pbEntityConverterGogoHelpers[name] != "" {
+			contents = append(contents, pbEntityConverterGogoHelpers[name])
+		} else if pbEntityConverterHelpers[name] != "" {
+			contents = append(contents, pbEntityConverterHelpers[name])
+		}
+	}
+	sort.Strings(contents)
+	content := gstr.ReplaceByMap(consts.TemplatePbEntityConverterHelperContent, g.MapStrStr{
+		tplVarPackageName: gfile.Basename(in.Converter),
+		tplVarHelpers:     gstr.Join(contents, "\n"),
+	})
+	content = gstr.Replace(
+		content, tplVarPackageImports,
+		getPbEntityConverterImports(content, cGenPbEntityInternalInput{cGenPbEntityInput: in}),
+	)
+	if err := putGeneratedContents(path, strings.TrimSpace(content), in.Force); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+	} else {
+		mlog.Print("generated:", path)
+	}
+}
+
+// getPbEntityConversion returns the conversion between entity type `entityType` and message type `pbType`.
+func getPbEntityConversion(entityType, pbType string, in cGenPbEntityInternalInput) pbEntityConversion {
+	// Time values are converted with time.Time.
+	if entityType == pbEntityGoTypeTime || entityType == pbEntityGoTypeGTime {
+		var toTime, fromTime = "%s", "%s"
+		conversion := pbEntityConversion{}
+		if entityType == pbEntityGoTypeGTime {
+			toTime, fromTime = "pbGTimeToTime(%s)", "pbTimeToGTime(%s)"
+			conversion.Helpers = []string{"pbGTimeToTime", "pbTimeToGTime"}
+		}
+		var toPb, fromPb string
+		switch pbType {
+		case "int64":
+			toPb, fromPb = "pbTimeToInt64", "pbInt64ToTime"
+		case pbTypeTimestamp:
+			toPb, fromPb = "pbTimeToTimestamp", "pbTimestampToTime"
+		case pbTypeDate:
+			toPb, fromPb = "pbTimeToDate", "pbDateToTime"
+		case pbTypeTimeOfDay:
+			toPb, fromPb = "pbTimeToTimeOfDay", "pbTimeOfDayToTime"
+		}
+		if toPb != "" {
+			conversion.ToPb = fmt.Sprintf("%s(%s)", toPb, toTime)
+			conversion.FromPb = fmt.Sprintf(fromTime, fromPb+"(%s)")
+			conversion.Helpers = append(conversion.Helpers, toPb, fromPb)
+			return conversion
+		}
+	}
+	// Json values are converted with string.
+	if pbType == pbTypeStruct {
+		switch entityType {
+		case "string":
+			return pbEntityConversion{
+				ToPb:    "pbStringToStruct(%s)",
+				FromPb:  "pbStructToString(%s)",
+				Helpers: []string{"pbStringToStruct", "pbStructToString"},
+			}
+		case pbEntityGoTypeGJson:
+			return pbEntityConversion{
+				ToPb:    "pbStringToStruct(pbGJsonToString(%s))",
+				FromPb:  "pbStringToGJson(pbStructToString(%s))",
+				Helpers: []string{"pbStringToStruct", "pbStructToString", "pbGJsonToString", "pbStringToGJson"},
+			}
+		}
+	}
+	// Wrapper values are converted with their scalar values.
+	for scalarType, wrapperType := range pbWrapperTypes {
+		if wrapperType != pbType {
+			continue
+		}
+		conversion := getPbEntityConversion(entityType, scalarType, in)
+		wrapperName := gstr.TrimLeftStr(wrapperType, "google.protobuf.")
+		if in.Style == pbEntityStylePlain {
+			conversion.ToPb = fmt.Sprintf("wrapperspb.%s(%s)", gstr.TrimRightStr(wrapperName, "Value"), conversion.ToPb)
+		} else {
+			conversion.ToPb = fmt.Sprintf("&types.%s{Value: %s}", wrapperName, conversion.ToPb)
+		}
+		if conversion.FromPb != "" {
+			conversion.FromPb = fmt.Sprintf(conversion.FromPb, "%s.GetValue()")
+		}
+		return conversion
+	}
+	// Scalar values.
+	goType := pbEntityGoTypes[pbType]
+	switch {
+	case goType == entityType:
+		return pbEntityConversion{ToPb: "%s", FromPb: "%s"}
+
+	case isPbEntityNumericType(goType) && isPbEntityNumericType(entityType):
+		return pbEntityConversion{ToPb: goType + "(%s)", FromPb: entityType + "(%s)"}
+
+	case goType == "string" && entityType == pbEntityGoTypeGJson:
+		return pbEntityConversion{
+			ToPb:    "pbGJsonToString(%s)",
+			FromPb:  "pbStringToGJson(%s)",
+			Helpers: []string{"pbGJsonToString", "pbStringToGJson"},
+		}
+
+	case goType != "":
+		// Custom types, like the ones of "@type" directive, are converted using gconv.
+		return pbEntityConversion{ToPb: fmt.Sprintf("gconv.%s(%%s)", gstr.UcFirst(gstr.Replace(goType, "[]byte", "bytes")))}
+	}
+	mlog.Fatalf(`unsupported conversion from "%s" to "%s"`, entityType, pbType)
+	return pbEntityConversion{}
+}
+
+// isPbEntityNumericType checks whether `goType` is a numeric golang type.
+func isPbEntityNumericType(goType string) bool {
+	switch goType {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64":
+		return true
+	}
+	return false
+}
+
+// getPbEntityConverterImports returns the imports of packages used by converter `content`,
+// which is checked by the package qualifiers, so that no unused import is generated.
+func getPbEntityConverterImports(content string, in cGenPbEntityInternalInput) string {
+	var (
+		stdImports = make([]string, 0)
+		imports    = make([]string, 0)
+		packages   = [][2]string{
+			{"time", `"time"`},
+			{"entity", fmt.Sprintf(`"%s"`, getPbEntityEntityImport(in.cGenPbEntityInput))},
+			{"pbentity", fmt.Sprintf(`pbentity "%s"`, getPbEntityMessageImport(in))},
+			{"gjson", `"github.com/gogf/gf/v2/encoding/gjson"`},
+			{"gtime", `"github.com/gogf/gf/v2/os/gtime"`},
+			{"gconv", `"github.com/gogf/gf/v2/util/gconv"`},
+			{"date", `"google.golang.org/genproto/googleapis/type/date"`},
+			{"timeofday", `"google.golang.org/genproto/googleapis/type/timeofday"`},
+		}
+	)
+	if in.Style == pbEntityStylePlain {
+		packages = append(packages,
+			[2]string{"structpb", `"google.golang.org/protobuf/types/known/structpb"`},
+			[2]string{"timestamppb", `"google.golang.org/protobuf/types/known/timestamppb"`},
+			[2]string{"wrapperspb", `"google.golang.org/protobuf/types/known/wrapperspb"`},
+		)
+	} else {
+		packages = append(packages,
+			[2]string{"jsonpb", `"github.com/gogo/protobuf/jsonpb"`},
+			[2]string{"types", `"github.com/gogo/protobuf/types"`},
+		)
+	}
+	for _, item := range packages {
+		switch {
+		case !gregex.IsMatchString(fmt.Sprintf(`\b%s\.`, item[0]), content):
+		case !gstr.Contains(item[1], "."):
+			stdImports = append(stdImports, item[1])
+		default:
+			imports = append(imports, item[1])
+		}
+	}
+	if len(stdImports) > 0 && len(imports) > 0 {
+		stdImports = append(stdImports, "")
+	}
+	return gstr.Join(append(stdImports, imports...), "\n")
+}
+
+// getPbEntityEntityImport returns the import path of entity package.
+func getPbEntityEntityImport(in cGenPbEntityInput) string {
+	if in.EntityImport != "" {
+		return in.EntityImport
+	}
+	return getPbEntityGoPackage(gfile.Join("internal", defaultEntityPath))
+}
+
+// getPbEntityMessageImport returns the import path of golang package of generated messages,
+// which is the "go_package" option, or else it is computed from the path of proto files.
+func getPbEntityMessageImport(in cGenPbEntityInternalInput) string {
+	match, _ := gregex.MatchString(`go_package\s*=\s*"([^";]+)`, getPbEntityOptions(in))
+	if len(match) > 1 {
+		return match[1]
+	}
+	return getPbEntityGoPackage(in.Path)
+}
+
+// getPbGoName returns the golang name of protobuf field or message name `name`,
+// which is the same as the one of protoc-gen-go.
+func getPbGoName(name string) string {
+	var (
+		b       = make([]byte, 0, len(name))
+		isLower = func(c byte) bool { return 'a' <= c && c <= 'z' }
+	)
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_' && i == 0:
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(name) && isLower(name[i+1]):
+			// Skip over '_' in "_{{lowercase}}".
+		case '0' <= c && c <= '9':
+			b = append(b, c)
+		default:
+			// Assume we have a letter now, if not, it's a bogus identifier.
+			if isLower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			// Accept lower case sequence that follows.
+			for ; i+1 < len(name) && isLower(name[i+1]); i++ {
+				b = append(b, name[i+1])
+			}
+		}
+	}
+	return string(b)
+}
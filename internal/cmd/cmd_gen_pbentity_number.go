@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// pbEntityFieldNumbers holds the field numbers of a message that were generated previously,
+// which keeps the field numbers stable across generations for wire compatibility.
+type pbEntityFieldNumbers struct {
+	Fields          map[string]int  // Fields maps the field names to their numbers.
+	ReservedNumbers map[int]bool    // ReservedNumbers are the numbers of removed fields.
+	ReservedNames   map[string]bool // ReservedNames are the names of removed fields.
+}
+
+// loadPbEntityFieldNumbers reads the message `messageName` from the existing proto file `path`,
+// it returns empty field numbers if the file or the message does not exist.
+func loadPbEntityFieldNumbers(path, messageName string) *pbEntityFieldNumbers {
+	numbers := &pbEntityFieldNumbers{
+		Fields:          make(map[string]int),
+		ReservedNumbers: make(map[int]bool),
+		ReservedNames:   make(map[string]bool),
+	}
+	if !gfile.Exists(path) {
+		return numbers
+	}
+	match, _ := gregex.MatchString(
+		fmt.Sprintf(`(?s)message\s+%s\s*\{(.*?)\n\s*\}`, gregex.Quote(messageName)),
+		gfile.GetContents(path),
+	)
+	if len(match) < 2 {
+		return numbers
+	}
+	for _, line := range gstr.SplitAndTrim(match[1], "\n") {
+		// Reserved numbers and names, eg: reserved 3, 5 to 7; reserved "Name";
+		if reserved, _ := gregex.MatchString(`^reserved\s+([^;]+);`, line); len(reserved) > 1 {
+			for _, item := range gstr.SplitAndTrim(reserved[1], ",") {
+				if gstr.HasPrefix(item, `"`) {
+					numbers.ReservedNames[gstr.Trim(item, `"`)] = true
+					continue
+				}
+				bounds := gstr.SplitAndTrim(item, "to")
+				from, to := gconv.Int(bounds[0]), gconv.Int(bounds[len(bounds)-1])
+				for i := from; i > 0 && i <= to; i++ {
+					numbers.ReservedNumbers[i] = true
+				}
+			}
+			continue
+		}
+		// Fields, eg: int64 Id = 1 [(gogoproto.jsontag) = "id"]; // comment
+		if field, _ := gregex.MatchString(`^(?:repeated\s+)?[\w.]+\s+(\w+)\s*=\s*(\d+)`, line); len(field) > 2 {
+			numbers.Fields[field[1]] = gconv.Int(field[2])
+		}
+	}
+	return numbers
+}
+
+// assign returns the field numbers for field names `names` in order,
+// the known fields keep their numbers and the new fields get numbers after all the used ones.
+// The fields that no longer exist are moved to the reserved numbers and names.
+func (numbers *pbEntityFieldNumbers) assign(names []string) []int {
+	var (
+		result  = make([]int, len(names))
+		current = make(map[string]bool)
+		maxUsed = 0
+	)
+	for _, number := range numbers.Fields {
+		if number > maxUsed {
+			maxUsed = number
+		}
+	}
+	for number := range numbers.ReservedNumbers {
+		if number > maxUsed {
+			maxUsed = number
+		}
+	}
+	for i, name := range names {
+		current[name] = true
+		if number, ok := numbers.Fields[name]; ok {
+			result[i] = number
+			continue
+		}
+		maxUsed++
+		result[i] = maxUsed
+		// The name of re-added field can be used again, but not its old number.
+		delete(numbers.ReservedNames, name)
+	}
+	for name, number := range numbers.Fields {
+		if !current[name] {
+			numbers.ReservedNumbers[number] = true
+			numbers.ReservedNames[name] = true
+		}
+	}
+	return result
+}
+
+// reservedDefinition returns the reserved statements of the message.
+func (numbers *pbEntityFieldNumbers) reservedDefinition() string {
+	var (
+		reservedNumbers = make([]int, 0, len(numbers.ReservedNumbers))
+		reservedNames   = make([]string, 0, len(numbers.ReservedNames))
+		definition      string
+	)
+	for number := range numbers.ReservedNumbers {
+		reservedNumbers = append(reservedNumbers, number)
+	}
+	for name := range numbers.ReservedNames {
+		reservedNames = append(reservedNames, fmt.Sprintf(`"%s"`, name))
+	}
+	sort.Ints(reservedNumbers)
+	sort.Strings(reservedNames)
+	if len(reservedNumbers) > 0 {
+		definition += fmt.Sprintf("    reserved %s;\n", gstr.Join(gconv.Strings(reservedNumbers), ", "))
+	}
+	if len(reservedNames) > 0 {
+		definition += fmt.Sprintf("    reserved %s;\n", gstr.Join(reservedNames, ", "))
+	}
+	return definition
+}
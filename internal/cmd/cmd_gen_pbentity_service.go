@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf-cli/v2/internal/consts"
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/olekukonko/tablewriter"
+)
+
+// pbServiceField is the field definition of request or response message of service.
+type pbServiceField struct {
+	Type    string // Type is the protobuf type, which can be prefixed with "optional" or "repeated".
+	Name    string // Name is the field name, which is formatted using option "nameCase".
+	Comment string // Comment is the comment of field.
+}
+
+// pbServiceFieldNames are the names of fields generated for all tables in request and response messages,
+// the indexed columns of the same names are prefixed as filter fields of List.
+var pbServiceFieldNames = []string{"page", "size", "list", "total", "entity"}
+
+// generatePbEntityServiceFile generates the CRUD service proto file of table,
+// the request and response messages reference the entity message `messageName` in proto file `entityPath`.
+func generatePbEntityServiceFile(
+	entityPath, messageName string, fieldMap map[string]*gdb.TableField, table *schemaTable, in cGenPbEntityInternalInput,
+) {
+	var (
+		name         = gstr.CaseCamel(in.Prefix + in.NewTableName)
+		serviceName  = name + "Service"
+		path         = gfile.Join(in.Service, gstr.Trim(gstr.CaseSnake(in.Prefix+in.NewTableName), "-_.")+".proto")
+		entityType   = in.Package + "." + messageName
+		keyFields    = make([]pbServiceField, 0)
+		filterFields = make([]pbServiceField, 0)
+		rpcLines     = make([]string, 0)
+		messages     = make([]string, 0)
+		filtered     = make(map[string]bool)
+	)
+	// Primary key fields for Get, Update and Delete.
+	if primaryKey := table.PrimaryKey(); primaryKey != nil {
+		for _, column := range primaryKey.Columns {
+			if field, ok := fieldMap[column]; ok {
+				keyFields = append(keyFields, getPbServiceField(field, "", in))
+			}
+		}
+	}
+	// Indexed columns are used as optional filters of List.
+	for _, column := range sortFieldKeyForPbEntity(fieldMap) {
+		for _, index := range table.Indexes {
+			if !filtered[column] && gstr.InArray(index.Columns, column) {
+				filtered[column] = true
+				field := getPbServiceField(fieldMap[column], "optional", in)
+				if gstr.InArray(pbServiceFieldNames, gstr.ToLower(gstr.CaseCamel(column))) {
+					field.Name = formatCase("filter_"+column, in.NameCase)
+				}
+				filterFields = append(filterFields, field)
+			}
+		}
+	}
+	var (
+		entityField = pbServiceField{Type: entityType, Name: formatCase("entity", in.NameCase)}
+		pagingField = []pbServiceField{
+			{Type: "int32", Name: formatCase("page", in.NameCase), Comment: "Page number, which starts from 1."},
+			{Type: "int32", Name: formatCase("size", in.NameCase), Comment: "Page size."},
+		}
+		addRpc = func(method, comment string, reqFields, resFields []pbServiceField) {
+			var (
+				reqName = method + name + "Req"
+				resName = method + name + "Res"
+			)
+			rpcLines = append(rpcLines, fmt.Sprintf(
+				"    // %s %s\n    rpc %s(%s) returns (%s);", method, comment, method, reqName, resName,
+			))
+			messages = append(messages, generatePbServiceMessage(reqName, reqFields))
+			messages = append(messages, generatePbServiceMessage(resName, resFields))
+		}
+	)
+	if len(keyFields) > 0 {
+		addRpc("Get", "returns the record by primary key.", keyFields, []pbServiceField{entityField})
+	}
+	addRpc(
+		"List", "returns the records of given page, which are filtered by indexed columns.",
+		append(pagingField, filterFields...),
+		append([]pbServiceField{
+			{Type: "repeated " + entityType, Name: formatCase("list", in.NameCase)},
+			{Type: "int32", Name: formatCase("total", in.NameCase), Comment: "Total count of filtered records."},
+		}, pagingField...),
+	)
+	addRpc("Create", "creates a record and returns its primary key.", []pbServiceField{entityField}, keyFields)
+	if len(keyFields) > 0 {
+		addRpc("Update", "updates the record by primary key.", []pbServiceField{entityField}, nil)
+		addRpc("Delete", "deletes the record by primary key.", keyFields, nil)
+	} else {
+		mlog.Printf(`table "%s" has no primary key, rpc "Get", "Update" and "Delete" are not generated`, in.TableName)
+	}
+	optionInput := in
+	optionInput.Path = in.Service
+	content := gstr.ReplaceByMap(consts.TemplatePbEntityServiceContent, g.MapStrStr{
+		"{PackageName}":    in.Package,
+		"{ImportContent}":  getPbServiceImports(entityPath, messages, in),
+		"{OptionContent}":  getPbEntityOptions(optionInput),
+		"{ServiceName}":    serviceName,
+		"{TableName}":      in.TableName,
+		"{RpcContent}":     gstr.Join(rpcLines, "\n"),
+		"{MessageContent}": gstr.Join(messages, "\n\n"),
+	})
+	if err := putGeneratedContents(path, strings.TrimSpace(content), in.Force); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+	} else {
+		mlog.Print("generated:", path)
+	}
+}
+
+// getPbServiceField returns the message field for table field `field`,
+// the nullable wrappers are not used as the field has `label` like "optional" for presence.
+func getPbServiceField(field *gdb.TableField, label string, in cGenPbEntityInternalInput) pbServiceField {
+	in.WktNullable = false
+	typeName := getPbEntityFieldType(field, in)
+	// Message types have presence themselves.
+	if label != "" && pbEntityGoTypes[typeName] != "" {
+		typeName = label + " " + typeName
+	}
+	return pbServiceField{
+		Type:    typeName,
+		Name:    formatCase(field.Name, in.NameCase),
+		Comment: formatComment(field.Comment),
+	}
+}
+
+// generatePbServiceMessage generates and returns the message definition of `name` with `fields`.
+func generatePbServiceMessage(name string, fields []pbServiceField) string {
+	if len(fields) == 0 {
+		return fmt.Sprintf("message %s {}", name)
+	}
+	var (
+		buffer = bytes.NewBuffer(nil)
+		array  = make([][]string, len(fields))
+	)
+	for i, field := range fields {
+		array[i] = []string{
+			"    #" + field.Type,
+			" #" + field.Name,
+			fmt.Sprintf(" #= %d;", i+1),
+			"",
+		}
+		if field.Comment != "" {
+			array[i][3] = " #// " + field.Comment
+		}
+	}
+	tw := tablewriter.NewWriter(buffer)
+	tw.SetBorder(false)
+	tw.SetRowLine(false)
+	tw.SetAutoWrapText(false)
+	tw.SetColumnSeparator("")
+	tw.AppendBulk(array)
+	tw.Render()
+	// Let's do this hack of table writer for indent!
+	lines := gstr.Split(gstr.Replace(buffer.String(), "  #", ""), "\n")
+	for i, line := range lines {
+		lines[i] = gstr.TrimRight(line)
+	}
+	return fmt.Sprintf("message %s {\n%s\n}", name, strings.Trim(gstr.Join(lines, "\n"), "\n"))
+}
+
+// getPbServiceImports returns the import lines of service proto file,
+// which are the entity proto file and the well-known types used by messages.
+func getPbServiceImports(entityPath string, messages []string, in cGenPbEntityInternalInput) string {
+	var (
+		content      = gstr.Join(messages, "\n")
+		rootPath     = gfile.Abs(in.ProtoRoot)
+		entityImport = gstr.TrimLeftStr(gfile.Abs(entityPath), rootPath+gfile.Separator)
+	)
+	if entityImport == gfile.Abs(entityPath) {
+		mlog.Fatalf(`proto file "%s" is not in proto root "%s"`, entityPath, in.ProtoRoot)
+	}
+	imports := []string{fmt.Sprintf(`import "%s";`, gstr.Replace(entityImport, gfile.Separator, "/"))}
+	for _, item := range pbWellKnownTypeImports {
+		if gregex.IsMatchString(item[0], content) {
+			imports = append(imports, fmt.Sprintf(`import "%s";`, item[1]))
+		}
+	}
+	return gstr.Join(imports, "\n")
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/util/gtag"
+)
+
+const (
+	cGenStatusBrief = `list the generated files that are modified, missing or orphaned`
+	cGenStatusEg    = `
+gf gen status
+gf gen status -a
+`
+	cGenStatusAd = `
+STATUS
+    The generated files of "gen dao", "gen pbentity", "tpl parse" and "pack" to go file are recorded
+    with their checksums in manifest file ".gf-gen-manifest.json" of working directory.
+    The status of generated file is one of:
+    modified  the file was modified after last generating, which is refused to be overwritten without "--force" option
+    missing   the file was deleted after last generating
+    orphaned  the file was not generated by the latest full generating of the same scope, eg: its table was dropped
+    ok        the file is the same as last generating, which is listed only with "-a" option
+`
+	cGenStatusBriefAll = `list all the generated files including the unchanged ones`
+)
+
+const (
+	generatedStatusOk       = "ok"
+	generatedStatusModified = "modified"
+	generatedStatusMissing  = "missing"
+	generatedStatusOrphaned = "orphaned"
+)
+
+func init() {
+	gtag.Sets(g.MapStrStr{
+		`cGenStatusBrief`:    cGenStatusBrief,
+		`cGenStatusEg`:       cGenStatusEg,
+		`cGenStatusAd`:       cGenStatusAd,
+		`cGenStatusBriefAll`: cGenStatusBriefAll,
+	})
+}
+
+type (
+	cGenStatusInput struct {
+		g.Meta `name:"status" brief:"{cGenStatusBrief}" eg:"{cGenStatusEg}" ad:"{cGenStatusAd}"`
+		All    bool `name:"all" short:"a" brief:"{cGenStatusBriefAll}" orphan:"true"`
+	}
+	cGenStatusOutput struct{}
+)
+
+func (c cGen) Status(ctx context.Context, in cGenStatusInput) (out *cGenStatusOutput, err error) {
+	if !gfile.Exists(generatedManifestFile) {
+		mlog.Printf(`manifest file "%s" does not exist in working directory`, generatedManifestFile)
+		return
+	}
+	var (
+		manifest = loadGeneratedManifest()
+		paths    = make([]string, 0, len(manifest.Files))
+		count    = 0
+	)
+	for path := range manifest.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		status := getGeneratedFileStatus(manifest, path)
+		if status == generatedStatusOk && !in.All {
+			continue
+		}
+		if status != generatedStatusOk {
+			count++
+		}
+		mlog.Printf(`%-9s %s`, status, path)
+	}
+	if count == 0 {
+		mlog.Print("all generated files are up to date")
+	}
+	return
+}
+
+// getGeneratedFileStatus returns the status of generated file `path` recorded in `manifest`.
+func getGeneratedFileStatus(manifest *generatedManifest, path string) string {
+	item := manifest.Files[path]
+	if !gfile.Exists(path) {
+		return generatedStatusMissing
+	}
+	if getGeneratedFileChecksum(path) != item.Checksum {
+		return generatedStatusModified
+	}
+	if item.Scope != "" && item.Run < manifest.Scopes[item.Scope] {
+		return generatedStatusOrphaned
+	}
+	return generatedStatusOk
+}
@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gtag"
+)
+
+const (
+	cGenTsConfig = `gfcli.gen.ts`
+	cGenTsBrief  = `generate TypeScript interfaces of entities from database tables`
+	cGenTsEg     = `
+gf gen ts
+gf gen ts -l "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+gf gen ts -p ./web/src/types -t "user*,order*"
+gf gen ts -r gf_ -j Snake -u optional
+`
+	cGenTsAd = `
+CONFIGURATION SUPPORT
+    Options are also supported by configuration file.
+    The configuration node name is "gfcli.gen.ts", for example(config.yaml):
+    gfcli:
+      gen:
+        ts:
+          link:         "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+          path:         "web/src/types"
+          removePrefix: "gf_"
+          jsonCase:     "CamelLower"
+    It's suggested using the same "prefix", "removePrefix", "jsonCase" and "gJsonSupport" options as "gf gen dao",
+    so that the interface names and property names match the json of generated entities.
+
+TYPE MAPPING
+    The properties are mapped from the entity fields generated by "gf gen dao":
+    integer and float fields   number
+    bool fields                boolean
+    time and date fields       string
+    string and binary fields   string, or union of string literals for enum columns and "@enum" directive
+    *gjson.Json fields         unknown
+    The nullable columns are mapped according to the "nullable" option:
+    null      "name: string | null", which is the default as the entity json always contains the field
+    optional  "name?: string"
+    both      "name?: string | null"
+    The fields having "@omitempty" directive are always optional, and the fields having "@json:-" directive are ignored.
+`
+	cGenTsBriefPath         = `directory path for generated TypeScript files`
+	cGenTsBriefLink         = `database configuration, the same as the ORM configuration of GoFrame`
+	cGenTsBriefTables       = `only the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cGenTsBriefTablesEx     = `excluding the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cGenTsBriefPrefix       = `add prefix for all table names, the same as "gf gen dao"`
+	cGenTsBriefRemovePrefix = `remove specified prefix of the table, multiple prefix separated with ',', the same as "gf gen dao"`
+	cGenTsBriefJsonCase     = `json case for interface properties, the same as "gf gen dao", default is "CamelLower"`
+	cGenTsBriefGJsonSupport = `use unknown for json fields like *gjson.Json of "gf gen dao", or else string`
+	cGenTsBriefNullable     = `mapping of nullable columns, which can be: null, optional, both. default is "null"`
+	cGenTsBriefForce        = `overwrite the generated files even if they were modified after last generating`
+	cGenTsBriefGroup        = `
+specifying the configuration group name of database,
+it's not necessary and the default value is "default"
+`
+)
+
+const (
+	tsNullableNull     = "null"
+	tsNullableOptional = "optional"
+	tsNullableBoth     = "both"
+)
+
+func init() {
+	gtag.Sets(g.MapStrStr{
+		`cGenTsConfig`:            cGenTsConfig,
+		`cGenTsBrief`:             cGenTsBrief,
+		`cGenTsEg`:                cGenTsEg,
+		`cGenTsAd`:                cGenTsAd,
+		`cGenTsBriefPath`:         cGenTsBriefPath,
+		`cGenTsBriefLink`:         cGenTsBriefLink,
+		`cGenTsBriefTables`:       cGenTsBriefTables,
+		`cGenTsBriefTablesEx`:     cGenTsBriefTablesEx,
+		`cGenTsBriefPrefix`:       cGenTsBriefPrefix,
+		`cGenTsBriefRemovePrefix`: cGenTsBriefRemovePrefix,
+		`cGenTsBriefJsonCase`:     cGenTsBriefJsonCase,
+		`cGenTsBriefGJsonSupport`: cGenTsBriefGJsonSupport,
+		`cGenTsBriefNullable`:     cGenTsBriefNullable,
+		`cGenTsBriefForce`:        cGenTsBriefForce,
+		`cGenTsBriefGroup`:        cGenTsBriefGroup,
+	})
+}
+
+type (
+	cGenTsInput struct {
+		g.Meta       `name:"ts" config:"{cGenTsConfig}" brief:"{cGenTsBrief}" eg:"{cGenTsEg}" ad:"{cGenTsAd}"`
+		Path         string `name:"path"         short:"p" brief:"{cGenTsBriefPath}" d:"manifest/typescript"`
+		Link         string `name:"link"         short:"l" brief:"{cGenTsBriefLink}"`
+		Group        string `name:"group"        short:"g" brief:"{cGenTsBriefGroup}" d:"default"`
+		Tables       string `name:"tables"       short:"t" brief:"{cGenTsBriefTables}"`
+		TablesEx     string `name:"tablesEx"     short:"e" brief:"{cGenTsBriefTablesEx}"`
+		Prefix       string `name:"prefix"       short:"x" brief:"{cGenTsBriefPrefix}"`
+		RemovePrefix string `name:"removePrefix" short:"r" brief:"{cGenTsBriefRemovePrefix}"`
+		JsonCase     string `name:"jsonCase"     short:"j" brief:"{cGenTsBriefJsonCase}" d:"CamelLower"`
+		GJsonSupport bool   `name:"gJsonSupport" short:"n" brief:"{cGenTsBriefGJsonSupport}" orphan:"true"`
+		Nullable     string `name:"nullable"     short:"u" brief:"{cGenTsBriefNullable}" d:"null"`
+		Force        bool   `name:"force"                  brief:"{cGenTsBriefForce}" orphan:"true"`
+	}
+	cGenTsOutput struct{}
+
+	// tsInterface is the TypeScript interface of a table entity.
+	tsInterface struct {
+		Name       string
+		Table      string
+		Comment    string
+		Properties []*tsProperty
+		Enums      []*tsEnum
+	}
+	// tsProperty is the TypeScript property of an entity field.
+	tsProperty struct {
+		Name       string
+		Type       string
+		Optional   bool
+		Nullable   bool
+		Comment    string
+		Deprecated bool
+	}
+	// tsEnum is the string union type of an enum column.
+	tsEnum struct {
+		Name   string
+		Values []string
+	}
+)
+
+func (c cGen) Ts(ctx context.Context, in cGenTsInput) (out *cGenTsOutput, err error) {
+	in.Nullable = gstr.ToLower(in.Nullable)
+	switch in.Nullable {
+	case tsNullableNull, tsNullableOptional, tsNullableBoth:
+	default:
+		mlog.Fatalf(`unsupported nullable mapping "%s", it should be one of: null, optional, both`, in.Nullable)
+	}
+	db := getDatabase(in.Link, in.Group)
+	if db == nil {
+		mlog.Fatal("database initialization failed")
+	}
+	tableNames, err := db.Tables(ctx)
+	if err != nil {
+		mlog.Fatalf("fetching tables failed: \n %v", err)
+	}
+	tableNames = filterTableNames(tableNames, in.Tables, in.TablesEx)
+	if len(tableNames) == 0 {
+		mlog.Fatal("no table matches the given table patterns")
+	}
+	tables, err := loadDatabaseSchema(ctx, db, tableNames)
+	if err != nil {
+		mlog.Fatalf("%+v", err)
+	}
+
+	beginGeneratedManifest(getGeneratedManifestScope("gen ts", in.Path, in.Link, in.Group))
+	defer endGeneratedManifest(in.Tables == "")
+
+	exports := make([]string, 0, len(tables))
+	for _, table := range tables {
+		var (
+			tsi      = getTsInterface(table, db.GetConfig().Type, in)
+			fileName = gstr.CaseSnake(tsi.Name)
+			path     = gfile.Join(in.Path, fileName+".ts")
+		)
+		if err = putGeneratedContents(path, generateTsInterface(tsi), in.Force); err != nil {
+			mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+		}
+		mlog.Print("generated:", path)
+		exports = append(exports, fmt.Sprintf("export * from './%s';", fileName))
+	}
+	if in.Tables == "" {
+		path := gfile.Join(in.Path, "index.ts")
+		content := getTsFileHeader() + gstr.Join(exports, "\n") + "\n"
+		if err = putGeneratedContents(path, content, in.Force); err != nil {
+			mlog.Fatalf("writing content to '%s' failed: %v", path, err)
+		}
+		mlog.Print("generated:", path)
+	}
+	mlog.Print("done!")
+	return
+}
+
+// getTsInterface converts the table schema to TypeScript interface,
+// in which the interface and property names are the same as the entity and json of "gen dao".
+func getTsInterface(table *schemaTable, dbType string, in cGenTsInput) *tsInterface {
+	newTableName := table.Name
+	for _, v := range gstr.SplitAndTrim(in.RemovePrefix, ",") {
+		newTableName = gstr.TrimLeftStr(newTableName, v, 1)
+	}
+	newTableName = in.Prefix + newTableName
+	var (
+		tsi = &tsInterface{
+			Name:    gstr.CaseCamel(newTableName),
+			Table:   table.Name,
+			Comment: formatComment(table.Comment),
+		}
+		structInput = generateStructDefinitionInput{
+			cGenDaoInternalInput: cGenDaoInternalInput{
+				cGenDaoInput: cGenDaoInput{
+					JsonCase:     in.JsonCase,
+					GJsonSupport: in.GJsonSupport,
+				},
+				DbType: dbType,
+			},
+		}
+	)
+	for i, column := range table.Columns {
+		var (
+			directives = parseFieldDirectives(column.Comment)
+			field      = &gdb.TableField{
+				Index:   i,
+				Name:    column.Name,
+				Type:    column.Type,
+				Null:    column.Null,
+				Comment: column.Comment,
+			}
+			goType   = gstr.Trim(generateStructFieldDefinition(field, structInput)[1], " #")
+			jsonName = gstr.Split(directives.JsonTag(getJsonTagFromCase(column.Name, in.JsonCase)), ",")[0]
+			property = &tsProperty{
+				Name:       jsonName,
+				Type:       getTsType(goType),
+				Optional:   directives.OmitEmpty,
+				Comment:    formatComment(column.Comment),
+				Deprecated: directives.Deprecated,
+			}
+		)
+		if directives.JsonIgnore {
+			continue
+		}
+		if enumValues := getColumnEnumValues(column.Type, directives); len(enumValues) > 0 {
+			enum := &tsEnum{
+				Name:   tsi.Name + gstr.CaseCamel(column.Name),
+				Values: enumValues,
+			}
+			tsi.Enums = append(tsi.Enums, enum)
+			property.Type = enum.Name
+		}
+		if column.Null {
+			switch in.Nullable {
+			case tsNullableNull:
+				property.Nullable = true
+			case tsNullableOptional:
+				property.Optional = true
+			case tsNullableBoth:
+				property.Nullable = true
+				property.Optional = true
+			}
+		}
+		tsi.Properties = append(tsi.Properties, property)
+	}
+	return tsi
+}
+
+// getTsType returns the TypeScript type of golang type `goType` generated for entity field.
+func getTsType(goType string) string {
+	switch goType {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	case "string", "[]byte", "*gtime.Time", "time.Time":
+		return "string"
+	default:
+		return "unknown"
+	}
+}
+
+// getColumnEnumValues returns the enum values of column, which are from "@enum" directive,
+// or from the column type like mysql "enum('a','b')".
+// The values of column type are quoted literals, which can contain commas and quotes escaped by doubling or backslash.
+func getColumnEnumValues(columnType string, directives fieldDirectives) []string {
+	if len(directives.Enum) > 0 {
+		return directives.Enum
+	}
+	match, _ := gregex.MatchString(`(?is)^enum\s*\((.+)\)$`, gstr.Trim(columnType))
+	if len(match) < 2 {
+		return nil
+	}
+	values := make([]string, 0)
+	for _, v := range splitSqlTopLevel(match[1], ',') {
+		values = append(values, unquoteEnumValue(gstr.Trim(v)))
+	}
+	return values
+}
+
+// unquoteEnumValue removes the quotes of enum literal `value`,
+// and unescapes the doubled quotes and backslash escaped chars in it.
+func unquoteEnumValue(value string) string {
+	if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+		return value
+	}
+	var (
+		buffer = bytes.NewBuffer(nil)
+		inner  = value[1 : len(value)-1]
+	)
+	for i := 0; i < len(inner); i++ {
+		if i+1 < len(inner) && (inner[i] == '\\' || inner[i] == '\'' && inner[i+1] == '\'') {
+			i++
+		}
+		buffer.WriteByte(inner[i])
+	}
+	return buffer.String()
+}
+
+// getTsFileHeader returns the header comment of generated TypeScript files.
+func getTsFileHeader() string {
+	return "// ==========================================================================\n" +
+		"// Code generated by GoFrame CLI tool. DO NOT EDIT.\n" +
+		"// ==========================================================================\n\n"
+}
+
+// generateTsInterface generates and returns the TypeScript content of interface `tsi`.
+func generateTsInterface(tsi *tsInterface) string {
+	var (
+		buffer  = bytes.NewBuffer(nil)
+		literal = func(s string) string {
+			return "'" + gstr.Replace(gstr.Replace(s, `\`, `\\`), `'`, `\'`) + "'"
+		}
+	)
+	buffer.WriteString(getTsFileHeader())
+	for _, enum := range tsi.Enums {
+		values := make([]string, len(enum.Values))
+		for i, v := range enum.Values {
+			values[i] = literal(v)
+		}
+		buffer.WriteString(fmt.Sprintf("export type %s = %s;\n\n", enum.Name, gstr.Join(values, " | ")))
+	}
+	buffer.WriteString("/**\n")
+	if tsi.Comment != "" {
+		buffer.WriteString(fmt.Sprintf(" * %s\n", getTsComment(tsi.Comment)))
+	}
+	buffer.WriteString(fmt.Sprintf(" * Entity of table %s.\n */\n", tsi.Table))
+	buffer.WriteString(fmt.Sprintf("export interface %s {\n", tsi.Name))
+	for _, property := range tsi.Properties {
+		if property.Comment != "" || property.Deprecated {
+			docs := make([]string, 0, 2)
+			if property.Comment != "" {
+				docs = append(docs, getTsComment(property.Comment))
+			}
+			if property.Deprecated {
+				docs = append(docs, "@deprecated")
+			}
+			buffer.WriteString(fmt.Sprintf("  /** %s */\n", gstr.Join(docs, " ")))
+		}
+		var (
+			name     = property.Name
+			typeName = property.Type
+		)
+		if !gregex.IsMatchString(`^[A-Za-z_$][\w$]*$`, name) {
+			name = literal(name)
+		}
+		if property.Optional {
+			name += "?"
+		}
+		if property.Nullable {
+			typeName += " | null"
+		}
+		buffer.WriteString(fmt.Sprintf("  %s: %s;\n", name, typeName))
+	}
+	buffer.WriteString("}\n")
+	return buffer.String()
+}
+
+// getTsComment returns the single line comment `comment` which is safe in TypeScript block comment.
+func getTsComment(comment string) string {
+	comment = strings.Join(strings.Fields(comment), " ")
+	return gstr.Replace(comment, "*/", "* /")
+}
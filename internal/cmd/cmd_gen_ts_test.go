@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func Test_getColumnEnumValues(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var cases = []struct {
+			columnType string
+			directives fieldDirectives
+			values     []string
+		}{
+			{columnType: "enum('a','b')", values: []string{"a", "b"}},
+			{columnType: "ENUM('a', 'b')", values: []string{"a", "b"}},
+			{columnType: "enum('a,b','c')", values: []string{"a,b", "c"}},
+			{columnType: "enum('it''s','x')", values: []string{"it's", "x"}},
+			{columnType: `enum('it\'s','a\\b')`, values: []string{"it's", `a\b`}},
+			{columnType: "enum('(a)',')')", values: []string{"(a)", ")"}},
+			{columnType: "enum('')", values: []string{""}},
+			{columnType: "enum('a')", directives: fieldDirectives{Enum: []string{"x", "y"}}, values: []string{"x", "y"}},
+			{columnType: "varchar(45)", values: nil},
+		}
+		for _, c := range cases {
+			t.Assert(getColumnEnumValues(c.columnType, c.directives), c.values)
+		}
+	})
+}
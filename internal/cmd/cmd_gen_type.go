@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+// Golang types of database fields that are shared by dialect specific mappings.
+// The time and json types are decided by the generating options.
+const (
+	dialectTypeTime = `time`
+	dialectTypeJson = `json`
+)
+
+// dialectTypeMappings is the mapping from database field type to golang type of specified database type.
+// The key of mapping is the lowercase field type name without length/precision, like: nvarchar, number.
+// The field types that are not in the mappings are converted by the common mapping.
+var dialectTypeMappings = map[string]map[string]string{
+	"mssql": {
+		"bit":              "bool",
+		"tinyint":          "int",
+		"smallint":         "int",
+		"int":              "int",
+		"bigint":           "int64",
+		"real":             "float32",
+		"float":            "float64",
+		"decimal":          "float64",
+		"numeric":          "float64",
+		"money":            "float64",
+		"smallmoney":       "float64",
+		"char":             "string",
+		"varchar":          "string",
+		"text":             "string",
+		"nchar":            "string",
+		"nvarchar":         "string",
+		"ntext":            "string",
+		"xml":              "string",
+		"sysname":          "string",
+		"uniqueidentifier": "string",
+		"date":             dialectTypeTime,
+		"time":             dialectTypeTime,
+		"datetime":         dialectTypeTime,
+		"datetime2":        dialectTypeTime,
+		"smalldatetime":    dialectTypeTime,
+		"datetimeoffset":   dialectTypeTime,
+		"binary":           "[]byte",
+		"varbinary":        "[]byte",
+		"image":            "[]byte",
+		"timestamp":        "[]byte", // It is the synonym of rowversion, not a datetime.
+		"rowversion":       "[]byte",
+	},
+	"oracle": {
+		"integer":          "int64",
+		"int":              "int64",
+		"smallint":         "int",
+		"binary_float":     "float32",
+		"binary_double":    "float64",
+		"float":            "float64",
+		"real":             "float64",
+		"char":             "string",
+		"nchar":            "string",
+		"varchar":          "string",
+		"varchar2":         "string",
+		"nvarchar2":        "string",
+		"clob":             "string",
+		"nclob":            "string",
+		"long":             "string",
+		"rowid":            "string",
+		"urowid":           "string",
+		"date":             dialectTypeTime,
+		"timestamp":        dialectTypeTime,
+		"raw":              "[]byte",
+		"long raw":         "[]byte",
+		"blob":             "[]byte",
+		"bfile":            "[]byte",
+		"json":             dialectTypeJson,
+		"interval day":     "string",
+		"interval year":    "string",
+		"binary_integer":   "int",
+		"pls_integer":      "int",
+		"double precision": "float64",
+	},
+}
+
+// getDialectFieldType returns the golang type of `field` using the mapping of database type `dbType`.
+// The returned type is one of the golang basic types or dialectTypeTime/dialectTypeJson,
+// it returns empty string if the field type is not specified in the mapping of `dbType`.
+func getDialectFieldType(dbType string, field *gdb.TableField) string {
+	dbType = gstr.ToLower(dbType)
+	mapping, ok := dialectTypeMappings[dbType]
+	if !ok {
+		return ""
+	}
+	var (
+		fieldType = gstr.ToLower(gstr.Trim(field.Type))
+		match, _  = gregex.MatchString(`^([a-z_0-9 ]+?)\s*(\((.+?)\))?(\s+.*)?$`, fieldType)
+		name      string
+		params    string
+	)
+	if len(match) > 1 {
+		name, params = gstr.Trim(match[1]), match[3]
+	}
+	// The NUMBER type is decided by its precision and scale.
+	if name == "number" && dbType == "oracle" {
+		return getDialectOracleNumberType(params)
+	}
+	// Multiple words type names, like: "timestamp with time zone", "long raw".
+	for _, prefix := range []string{"timestamp", "interval day", "interval year", "long raw", "double precision"} {
+		if gstr.HasPrefix(fieldType, prefix) {
+			if v, ok := mapping[prefix]; ok {
+				return v
+			}
+		}
+	}
+	return mapping[name]
+}
+
+// getDialectOracleNumberType returns the golang type for oracle type NUMBER(p,s).
+// It is integer if the scale is zero, or else it is float.
+func getDialectOracleNumberType(params string) string {
+	array := gstr.SplitAndTrim(params, ",")
+	if len(array) == 0 || array[0] == "*" {
+		return "float64"
+	}
+	var (
+		precision = gconv.Int(array[0])
+		scale     = 0
+	)
+	if len(array) > 1 {
+		scale = gconv.Int(array[1])
+	}
+	switch {
+	case scale > 0:
+		return "float64"
+	case precision <= 9:
+		return "int"
+	case precision <= 18:
+		return "int64"
+	default:
+		// It overflows int64, so it uses float64 for compatibility.
+		return "float64"
+	}
+}
+
+// getDialectGoType returns the golang type name of `field` for generating go files,
+// it returns empty string if it is not specified by dialect mapping.
+func getDialectGoType(dbType string, field *gdb.TableField, stdTime, gJsonSupport bool) string {
+	switch typeName := getDialectFieldType(dbType, field); typeName {
+	case dialectTypeTime:
+		if stdTime {
+			return "time.Time"
+		}
+		return "*gtime.Time"
+	case dialectTypeJson:
+		if gJsonSupport {
+			return "*gjson.Json"
+		}
+		return "string"
+	default:
+		return typeName
+	}
+}
+
+// getDialectPbType returns the protobuf type name of `field` for generating proto files,
+// it returns empty string if it is not specified by dialect mapping.
+func getDialectPbType(dbType string, field *gdb.TableField) string {
+	switch getDialectFieldType(dbType, field) {
+	case "bool":
+		return "bool"
+	case "int":
+		return "int32"
+	case "int64":
+		return "int64"
+	case "float32":
+		return "float"
+	case "float64":
+		return "double"
+	case "string", dialectTypeJson:
+		return "string"
+	case "[]byte":
+		return "bytes"
+	case dialectTypeTime:
+		return "int64"
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func Test_getDialectFieldType(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var cases = []struct {
+			dbType    string
+			fieldType string
+			goType    string
+		}{
+			{dbType: "mssql", fieldType: "bit", goType: "bool"},
+			{dbType: "mssql", fieldType: "bigint", goType: "int64"},
+			{dbType: "mssql", fieldType: "decimal(10,2)", goType: "float64"},
+			{dbType: "mssql", fieldType: "nvarchar(max)", goType: "string"},
+			{dbType: "mssql", fieldType: "uniqueidentifier", goType: "string"},
+			{dbType: "mssql", fieldType: "datetime2(7)", goType: dialectTypeTime},
+			{dbType: "mssql", fieldType: "timestamp", goType: "[]byte"},
+			{dbType: "MSSQL", fieldType: "VARBINARY(16)", goType: "[]byte"},
+			{dbType: "mssql", fieldType: "geography", goType: ""},
+			{dbType: "oracle", fieldType: "VARCHAR2(45)", goType: "string"},
+			{dbType: "oracle", fieldType: "DATE", goType: dialectTypeTime},
+			{dbType: "oracle", fieldType: "TIMESTAMP(6) WITH TIME ZONE", goType: dialectTypeTime},
+			{dbType: "oracle", fieldType: "INTERVAL DAY(2) TO SECOND(6)", goType: "string"},
+			{dbType: "oracle", fieldType: "LONG RAW", goType: "[]byte"},
+			{dbType: "oracle", fieldType: "BINARY_DOUBLE", goType: "float64"},
+			{dbType: "oracle", fieldType: "JSON", goType: dialectTypeJson},
+			{dbType: "oracle", fieldType: "NUMBER(5)", goType: "int"},
+			{dbType: "oracle", fieldType: "NUMBER(12,0)", goType: "int64"},
+			{dbType: "oracle", fieldType: "NUMBER(10,2)", goType: "float64"},
+			{dbType: "ORACLE", fieldType: "NUMBER", goType: "float64"},
+			{dbType: "mysql", fieldType: "int(10)", goType: ""},
+			{dbType: "", fieldType: "varchar(45)", goType: ""},
+		}
+		for _, c := range cases {
+			t.Assert(getDialectFieldType(c.dbType, &gdb.TableField{Type: c.fieldType}), c.goType)
+		}
+	})
+}
+
+func Test_getDialectOracleNumberType(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var cases = []struct {
+			params string
+			goType string
+		}{
+			{params: "", goType: "float64"},
+			{params: "*", goType: "float64"},
+			{params: "*,0", goType: "float64"},
+			{params: "1", goType: "int"},
+			{params: "9", goType: "int"},
+			{params: "9,0", goType: "int"},
+			{params: "10", goType: "int64"},
+			{params: "18, 0", goType: "int64"},
+			{params: "19", goType: "float64"},
+			{params: "38", goType: "float64"},
+			{params: "5,2", goType: "float64"},
+			{params: "18,1", goType: "float64"},
+			{params: "7,-2", goType: "int"},
+		}
+		for _, c := range cases {
+			t.Assert(getDialectOracleNumberType(c.params), c.goType)
+		}
+	})
+}
@@ -0,0 +1,839 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
+	"github.com/gogf/gf/v2/util/gtag"
+	"github.com/olekukonko/tablewriter"
+)
+
+var (
+	Migrate = cMigrate{}
+)
+
+type cMigrate struct {
+	g.Meta `name:"migrate" brief:"{cMigrateBrief}" dc:"{cMigrateDc}" ad:"{cMigrateAd}"`
+}
+
+const (
+	cMigrateConfig       = `gfcli.migrate`
+	cMigrateDefaultPath  = `manifest/migration`
+	cMigrateDefaultTable = `gf_migration`
+	cMigrateBrief        = `manage versioned database migrations using up/down sql files`
+	cMigrateDc           = `
+The "migrate" command manages the database schema using versioned sql files.
+Each migration contains two files named "{VERSION}_{NAME}.up.sql" and "{VERSION}_{NAME}.down.sql",
+in which the VERSION is the creating timestamp like "20220101150405".
+The applied versions are recorded in a migration table of the database.
+Please use "gf migrate up -h" for specified command help.
+`
+	cMigrateAd = `
+CONFIGURATION SUPPORT
+    Options are also supported by configuration file.
+    The database configuration is the same as "gf gen dao", which uses "link" or "group" of the ORM configuration.
+    The configuration node name is "gfcli.migrate", for example(config.yaml):
+    gfcli:
+      migrate:
+        link:   "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+        path:   "manifest/migration"
+        table:  "gf_migration"
+        genDao: true
+`
+	cMigrateCreateBrief = `create a new pair of up/down migration files`
+	cMigrateCreateEg    = `
+gf migrate create create_user_table
+gf migrate create add_user_nickname -p ./manifest/migration
+`
+	cMigrateUpBrief = `apply all or N pending migrations`
+	cMigrateUpEg    = `
+gf migrate up
+gf migrate up -n 1
+gf migrate up -l "mysql:root:12345678@tcp(127.0.0.1:3306)/test"
+gf migrate up --genDao
+`
+	cMigrateDownBrief = `revert the last or N applied migrations`
+	cMigrateDownEg    = `
+gf migrate down
+gf migrate down -n 3
+gf migrate down -a
+`
+	cMigrateStatusBrief = `show the applied and pending status of all migrations`
+	cMigrateStatusEg    = `
+gf migrate status
+gf migrate status -g user-center
+`
+	cMigrateGotoBrief = `migrate up or down to specified version`
+	cMigrateGotoEg    = `
+gf migrate goto 20220101150405
+`
+	cMigrateForceBrief = `set the database migration version without running any migration, which also clears the dirty state`
+	cMigrateForceEg    = `
+gf migrate force 20220101150405
+gf migrate force 0
+`
+	cMigrateBriefPath    = `directory path for migration files, default is "manifest/migration"`
+	cMigrateBriefLink    = `database configuration, the same as the ORM configuration of GoFrame`
+	cMigrateBriefTable   = `table name recording the applied migration versions, default is "gf_migration"`
+	cMigrateBriefName    = `name for the migration, which is used as part of the migration file names`
+	cMigrateBriefVersion = `version of the migration, use 0 to specify the state that no migration is applied`
+	cMigrateBriefSteps   = `count of migrations to be applied or reverted`
+	cMigrateBriefAll     = `revert all applied migrations`
+	cMigrateBriefNoTx    = `do not wrap each migration in transaction even if the database supports transactional DDL`
+	cMigrateBriefGenDao  = `run "gf gen dao" after migrations are successfully applied`
+	cMigrateBriefGroup   = `
+specifying the configuration group name of database,
+it's not necessary and the default value is "default"
+`
+)
+
+const (
+	migrationFileUpSuffix   = `.up.sql`
+	migrationFileDownSuffix = `.down.sql`
+	migrationVersionLayout  = `YmdHis`
+)
+
+func init() {
+	gtag.Sets(g.MapStrStr{
+		`cMigrateBrief`:        cMigrateBrief,
+		`cMigrateDc`:           cMigrateDc,
+		`cMigrateAd`:           cMigrateAd,
+		`cMigrateCreateBrief`:  cMigrateCreateBrief,
+		`cMigrateCreateEg`:     cMigrateCreateEg,
+		`cMigrateUpBrief`:      cMigrateUpBrief,
+		`cMigrateUpEg`:         cMigrateUpEg,
+		`cMigrateDownBrief`:    cMigrateDownBrief,
+		`cMigrateDownEg`:       cMigrateDownEg,
+		`cMigrateStatusBrief`:  cMigrateStatusBrief,
+		`cMigrateStatusEg`:     cMigrateStatusEg,
+		`cMigrateGotoBrief`:    cMigrateGotoBrief,
+		`cMigrateGotoEg`:       cMigrateGotoEg,
+		`cMigrateForceBrief`:   cMigrateForceBrief,
+		`cMigrateForceEg`:      cMigrateForceEg,
+		`cMigrateBriefPath`:    cMigrateBriefPath,
+		`cMigrateBriefLink`:    cMigrateBriefLink,
+		`cMigrateBriefTable`:   cMigrateBriefTable,
+		`cMigrateBriefName`:    cMigrateBriefName,
+		`cMigrateBriefVersion`: cMigrateBriefVersion,
+		`cMigrateBriefSteps`:   cMigrateBriefSteps,
+		`cMigrateBriefAll`:     cMigrateBriefAll,
+		`cMigrateBriefNoTx`:    cMigrateBriefNoTx,
+		`cMigrateBriefGenDao`:  cMigrateBriefGenDao,
+		`cMigrateBriefGroup`:   cMigrateBriefGroup,
+	})
+}
+
+type (
+	// cMigrateCommonInput is the common database and path options for all migrate commands.
+	cMigrateCommonInput struct {
+		Path  string
+		Link  string
+		Group string
+		Table string
+	}
+
+	cMigrateCreateInput struct {
+		g.Meta `name:"create" brief:"{cMigrateCreateBrief}" eg:"{cMigrateCreateEg}"`
+		Name   string `name:"NAME" arg:"true" v:"required" brief:"{cMigrateBriefName}"`
+		Path   string `name:"path" short:"p"  brief:"{cMigrateBriefPath}"`
+	}
+	cMigrateCreateOutput struct{}
+
+	cMigrateUpInput struct {
+		g.Meta `name:"up" brief:"{cMigrateUpBrief}" eg:"{cMigrateUpEg}"`
+		Path   string `name:"path"   short:"p" brief:"{cMigrateBriefPath}"`
+		Link   string `name:"link"   short:"l" brief:"{cMigrateBriefLink}"`
+		Group  string `name:"group"  short:"g" brief:"{cMigrateBriefGroup}"`
+		Table  string `name:"table"  short:"t" brief:"{cMigrateBriefTable}"`
+		Steps  int    `name:"steps"  short:"n" brief:"{cMigrateBriefSteps}"`
+		NoTx   bool   `name:"noTx"   short:"x" brief:"{cMigrateBriefNoTx}"   orphan:"true"`
+		GenDao bool   `name:"genDao" short:"d" brief:"{cMigrateBriefGenDao}" orphan:"true"`
+	}
+	cMigrateUpOutput struct{}
+
+	cMigrateDownInput struct {
+		g.Meta `name:"down" brief:"{cMigrateDownBrief}" eg:"{cMigrateDownEg}"`
+		Path   string `name:"path"   short:"p" brief:"{cMigrateBriefPath}"`
+		Link   string `name:"link"   short:"l" brief:"{cMigrateBriefLink}"`
+		Group  string `name:"group"  short:"g" brief:"{cMigrateBriefGroup}"`
+		Table  string `name:"table"  short:"t" brief:"{cMigrateBriefTable}"`
+		Steps  int    `name:"steps"  short:"n" brief:"{cMigrateBriefSteps}" d:"1"`
+		All    bool   `name:"all"    short:"a" brief:"{cMigrateBriefAll}"  orphan:"true"`
+		NoTx   bool   `name:"noTx"   short:"x" brief:"{cMigrateBriefNoTx}" orphan:"true"`
+	}
+	cMigrateDownOutput struct{}
+
+	cMigrateStatusInput struct {
+		g.Meta `name:"status" brief:"{cMigrateStatusBrief}" eg:"{cMigrateStatusEg}"`
+		Path   string `name:"path"   short:"p" brief:"{cMigrateBriefPath}"`
+		Link   string `name:"link"   short:"l" brief:"{cMigrateBriefLink}"`
+		Group  string `name:"group"  short:"g" brief:"{cMigrateBriefGroup}"`
+		Table  string `name:"table"  short:"t" brief:"{cMigrateBriefTable}"`
+	}
+	cMigrateStatusOutput struct{}
+
+	cMigrateGotoInput struct {
+		g.Meta  `name:"goto" brief:"{cMigrateGotoBrief}" eg:"{cMigrateGotoEg}"`
+		Version string `name:"VERSION" arg:"true" v:"required" brief:"{cMigrateBriefVersion}"`
+		Path    string `name:"path"   short:"p" brief:"{cMigrateBriefPath}"`
+		Link    string `name:"link"   short:"l" brief:"{cMigrateBriefLink}"`
+		Group   string `name:"group"  short:"g" brief:"{cMigrateBriefGroup}"`
+		Table   string `name:"table"  short:"t" brief:"{cMigrateBriefTable}"`
+		NoTx    bool   `name:"noTx"    short:"x" brief:"{cMigrateBriefNoTx}"   orphan:"true"`
+		GenDao  bool   `name:"genDao"  short:"d" brief:"{cMigrateBriefGenDao}" orphan:"true"`
+	}
+	cMigrateGotoOutput struct{}
+
+	cMigrateForceInput struct {
+		g.Meta  `name:"force" brief:"{cMigrateForceBrief}" eg:"{cMigrateForceEg}"`
+		Version string `name:"VERSION" arg:"true" v:"required" brief:"{cMigrateBriefVersion}"`
+		Path    string `name:"path"   short:"p" brief:"{cMigrateBriefPath}"`
+		Link    string `name:"link"   short:"l" brief:"{cMigrateBriefLink}"`
+		Group   string `name:"group"  short:"g" brief:"{cMigrateBriefGroup}"`
+		Table   string `name:"table"  short:"t" brief:"{cMigrateBriefTable}"`
+	}
+	cMigrateForceOutput struct{}
+)
+
+type (
+	// migrationFile is a pair of up/down migration files of certain version.
+	migrationFile struct {
+		Version  int64  // Version is the timestamp version of the migration.
+		Name     string // Name is the descriptive name of the migration.
+		UpPath   string // UpPath is the file path of the up sql.
+		DownPath string // DownPath is the file path of the down sql.
+	}
+	// migrationRecord is the applied migration record in migration table.
+	migrationRecord struct {
+		Version   int64  // Version is the timestamp version of the migration.
+		Name      string // Name is the descriptive name of the migration.
+		Dirty     bool   // Dirty marks the migration failed in the middle of applying or reverting.
+		AppliedAt string // AppliedAt is the time the migration is applied.
+	}
+	// migrator applies or reverts migrations for certain database.
+	migrator struct {
+		in   cMigrateCommonInput // in is the database and path options of migration.
+		db   gdb.DB              // db is the database to be migrated.
+		noTx bool                // noTx disables the transaction wrapping for each migration.
+	}
+)
+
+func (c cMigrate) Create(ctx context.Context, in cMigrateCreateInput) (out *cMigrateCreateOutput, err error) {
+	common := cMigrateCommonInput{Path: in.Path}
+	loadMigrateConfig(ctx, &common)
+	writeMigrationFiles(common.Path, in.Name, "", "")
+	mlog.Print("done!")
+	return
+}
+
+func (c cMigrate) Up(ctx context.Context, in cMigrateUpInput) (out *cMigrateUpOutput, err error) {
+	var (
+		m = newMigrator(ctx, cMigrateCommonInput{
+			Path:  in.Path,
+			Link:  in.Link,
+			Group: in.Group,
+			Table: in.Table,
+		}, in.NoTx)
+		files   = m.mustLoadFiles()
+		records = m.mustLoadRecords(ctx)
+		count   int
+	)
+	for _, file := range files {
+		if _, ok := records[file.Version]; ok {
+			continue
+		}
+		if in.Steps > 0 && count >= in.Steps {
+			break
+		}
+		if err = m.apply(ctx, file); err != nil {
+			mlog.Fatal(err)
+		}
+		count++
+	}
+	if count == 0 {
+		mlog.Print("no pending migration")
+	} else if in.GenDao || g.Cfg().MustGet(ctx, cMigrateConfig+".genDao").Bool() {
+		runGenDaoAfterMigration(ctx, m)
+	}
+	mlog.Print("done!")
+	return
+}
+
+func (c cMigrate) Down(ctx context.Context, in cMigrateDownInput) (out *cMigrateDownOutput, err error) {
+	var (
+		m = newMigrator(ctx, cMigrateCommonInput{
+			Path:  in.Path,
+			Link:  in.Link,
+			Group: in.Group,
+			Table: in.Table,
+		}, in.NoTx)
+		files   = m.mustLoadFiles()
+		records = m.mustLoadRecords(ctx)
+		count   int
+	)
+	for i := len(files) - 1; i >= 0; i-- {
+		if _, ok := records[files[i].Version]; !ok {
+			continue
+		}
+		if !in.All && count >= in.Steps {
+			break
+		}
+		if err = m.revert(ctx, files[i]); err != nil {
+			mlog.Fatal(err)
+		}
+		count++
+	}
+	if count == 0 {
+		mlog.Print("no applied migration")
+	}
+	mlog.Print("done!")
+	return
+}
+
+func (c cMigrate) Status(ctx context.Context, in cMigrateStatusInput) (out *cMigrateStatusOutput, err error) {
+	var (
+		m = newMigrator(ctx, cMigrateCommonInput{
+			Path:  in.Path,
+			Link:  in.Link,
+			Group: in.Group,
+			Table: in.Table,
+		}, false)
+		files   = m.mustLoadFiles()
+		records = m.mustLoadRecords(ctx)
+		array   = make([][]string, 0)
+		buffer  = bytes.NewBuffer(nil)
+	)
+	for _, file := range files {
+		var (
+			status    = "pending"
+			appliedAt string
+		)
+		if record, ok := records[file.Version]; ok {
+			status = "applied"
+			if record.Dirty {
+				status = "dirty"
+			}
+			appliedAt = record.AppliedAt
+			delete(records, file.Version)
+		}
+		array = append(array, []string{gconv.String(file.Version), file.Name, status, appliedAt})
+	}
+	// Applied migrations whose files are missing.
+	for _, record := range records {
+		array = append(array, []string{gconv.String(record.Version), record.Name, "missing", record.AppliedAt})
+	}
+	sort.SliceStable(array, func(i, j int) bool {
+		return array[i][0] < array[j][0]
+	})
+	if len(array) == 0 {
+		mlog.Printf(`no migration found in "%s"`, m.in.Path)
+		return
+	}
+	tw := tablewriter.NewWriter(buffer)
+	tw.SetHeader([]string{"Version", "Name", "Status", "Applied At"})
+	tw.SetAutoWrapText(false)
+	tw.AppendBulk(array)
+	tw.Render()
+	mlog.Print(buffer.String())
+	return
+}
+
+func (c cMigrate) Goto(ctx context.Context, in cMigrateGotoInput) (out *cMigrateGotoOutput, err error) {
+	var (
+		version = mustParseMigrationVersion(in.Version)
+		m       = newMigrator(ctx, cMigrateCommonInput{
+			Path:  in.Path,
+			Link:  in.Link,
+			Group: in.Group,
+			Table: in.Table,
+		}, in.NoTx)
+		files   = m.mustLoadFiles()
+		records = m.mustLoadRecords(ctx)
+		applied int
+	)
+	if version > 0 && m.searchFile(files, version) == nil {
+		mlog.Fatalf(`migration version "%d" not found in "%s"`, version, m.in.Path)
+	}
+	// Revert the applied migrations that are newer than given version.
+	for i := len(files) - 1; i >= 0; i-- {
+		if _, ok := records[files[i].Version]; !ok || files[i].Version <= version {
+			continue
+		}
+		if err = m.revert(ctx, files[i]); err != nil {
+			mlog.Fatal(err)
+		}
+	}
+	// Apply the pending migrations that are not newer than given version.
+	for _, file := range files {
+		if _, ok := records[file.Version]; ok || file.Version > version {
+			continue
+		}
+		if err = m.apply(ctx, file); err != nil {
+			mlog.Fatal(err)
+		}
+		applied++
+	}
+	if applied > 0 && (in.GenDao || g.Cfg().MustGet(ctx, cMigrateConfig+".genDao").Bool()) {
+		runGenDaoAfterMigration(ctx, m)
+	}
+	mlog.Print("done!")
+	return
+}
+
+func (c cMigrate) Force(ctx context.Context, in cMigrateForceInput) (out *cMigrateForceOutput, err error) {
+	var (
+		version = mustParseMigrationVersion(in.Version)
+		m       = newMigrator(ctx, cMigrateCommonInput{
+			Path:  in.Path,
+			Link:  in.Link,
+			Group: in.Group,
+			Table: in.Table,
+		}, false)
+		files = m.mustLoadFiles()
+	)
+	if version > 0 && m.searchFile(files, version) == nil {
+		mlog.Fatalf(`migration version "%d" not found in "%s"`, version, m.in.Path)
+	}
+	// The dirty check is not necessary for forcing.
+	records, err := m.loadRecords(ctx)
+	if err != nil {
+		mlog.Fatal(err)
+	}
+	err = m.db.Transaction(ctx, func(ctx context.Context, tx *gdb.TX) error {
+		if _, err := tx.Model(m.in.Table).Ctx(ctx).Where("version > ?", version).Delete(); err != nil {
+			return err
+		}
+		if _, err := tx.Model(m.in.Table).Ctx(ctx).Data(g.Map{"dirty": 0}).Where("version <= ?", version).Update(); err != nil {
+			return err
+		}
+		for _, file := range files {
+			if _, ok := records[file.Version]; ok || file.Version > version {
+				continue
+			}
+			if _, err := tx.Model(m.in.Table).Ctx(ctx).Data(newMigrationRecordData(file, false)).Insert(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		mlog.Fatalf(`forcing migration version "%d" failed: %+v`, version, err)
+	}
+	mlog.Printf(`migration version is forced to "%d"`, version)
+	mlog.Print("done!")
+	return
+}
+
+// mustParseMigrationVersion parses and returns the migration version from command line argument.
+func mustParseMigrationVersion(s string) int64 {
+	if !gregex.IsMatchString(`^\d+$`, s) {
+		mlog.Fatalf(`invalid migration version "%s"`, s)
+	}
+	return gconv.Int64(s)
+}
+
+// loadMigrateConfig loads the migration configuration from configuration file
+// for options that are not passed from command line, and sets their default values.
+func loadMigrateConfig(ctx context.Context, in *cMigrateCommonInput) {
+	if g.Cfg().Available(ctx) {
+		var config *cMigrateCommonInput
+		if err := g.Cfg().MustGet(ctx, cMigrateConfig).Scan(&config); err != nil {
+			mlog.Fatalf(`invalid configuration of "%s": %+v`, cMigrateConfig, err)
+		}
+		if config != nil {
+			if in.Path == "" {
+				in.Path = config.Path
+			}
+			if in.Link == "" {
+				in.Link = config.Link
+			}
+			if in.Group == "" {
+				in.Group = config.Group
+			}
+			if in.Table == "" {
+				in.Table = config.Table
+			}
+		}
+	}
+	if in.Path == "" {
+		in.Path = cMigrateDefaultPath
+	}
+	if in.Group == "" {
+		in.Group = gdb.DefaultGroupName
+	}
+	if in.Table == "" {
+		in.Table = cMigrateDefaultTable
+	}
+}
+
+// newMigrator creates and returns a migrator using given options.
+func newMigrator(ctx context.Context, in cMigrateCommonInput, noTx bool) *migrator {
+	loadMigrateConfig(ctx, &in)
+	db := getDatabase(in.Link, in.Group)
+	if db == nil {
+		mlog.Fatal("database initialization failed")
+	}
+	m := &migrator{
+		in:   in,
+		db:   db,
+		noTx: noTx,
+	}
+	return m
+}
+
+// createTableIfNotExists creates the migration table if it does not exist.
+func (m *migrator) createTableIfNotExists(ctx context.Context) error {
+	tables, err := m.db.Tables(ctx)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if table == m.in.Table {
+			return nil
+		}
+	}
+	var (
+		core     = m.db.GetCore()
+		timeType = "DATETIME"
+	)
+	switch m.db.GetConfig().Type {
+	case "pgsql":
+		timeType = "TIMESTAMP"
+	case "oracle":
+		timeType = "DATE"
+	}
+	_, err = m.db.Exec(ctx, fmt.Sprintf(
+		"CREATE TABLE %s (%s BIGINT NOT NULL PRIMARY KEY, %s VARCHAR(255) NOT NULL, %s SMALLINT NOT NULL DEFAULT 0, %s %s NULL)",
+		core.QuoteWord(m.in.Table),
+		core.QuoteWord("version"),
+		core.QuoteWord("name"),
+		core.QuoteWord("dirty"),
+		core.QuoteWord("applied_at"),
+		timeType,
+	))
+	return err
+}
+
+// supportsTransactionalDDL checks and returns whether the database rollbacks DDL statements in transaction.
+func (m *migrator) supportsTransactionalDDL() bool {
+	if m.noTx {
+		return false
+	}
+	switch m.db.GetConfig().Type {
+	case "pgsql", "mssql", "sqlite":
+		return true
+	}
+	return false
+}
+
+// mustLoadFiles scans and returns all migration files sorted by version ascending.
+func (m *migrator) mustLoadFiles() []*migrationFile {
+	if !gfile.Exists(m.in.Path) {
+		mlog.Fatalf(`migration path "%s" does not exist`, m.in.Path)
+	}
+	paths, err := gfile.ScanDirFile(m.in.Path, "*.sql", false)
+	if err != nil {
+		mlog.Fatal(err)
+	}
+	fileMap := make(map[int64]*migrationFile)
+	for _, path := range paths {
+		match, _ := gregex.MatchString(`^(\d+)_(\w+)\.(up|down)\.sql$`, gfile.Basename(path))
+		if len(match) != 4 {
+			mlog.Debugf(`ignore invalid migration file name "%s"`, path)
+			continue
+		}
+		version := gconv.Int64(match[1])
+		file, ok := fileMap[version]
+		if !ok {
+			file = &migrationFile{Version: version, Name: match[2]}
+			fileMap[version] = file
+		} else if file.Name != match[2] {
+			mlog.Fatalf(`migration version "%d" has different names "%s" and "%s"`, version, file.Name, match[2])
+		}
+		if match[3] == "up" {
+			file.UpPath = path
+		} else {
+			file.DownPath = path
+		}
+	}
+	files := make([]*migrationFile, 0, len(fileMap))
+	for _, file := range fileMap {
+		if file.UpPath == "" || file.DownPath == "" {
+			mlog.Fatalf(`migration "%d_%s" should have both up and down files`, file.Version, file.Name)
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Version < files[j].Version
+	})
+	return files
+}
+
+// searchFile searches and returns the migration file of given version.
+func (m *migrator) searchFile(files []*migrationFile, version int64) *migrationFile {
+	for _, file := range files {
+		if file.Version == version {
+			return file
+		}
+	}
+	return nil
+}
+
+// loadRecords retrieves and returns all applied migration records.
+// It creates the migration table if it does not exist.
+func (m *migrator) loadRecords(ctx context.Context) (map[int64]*migrationRecord, error) {
+	if err := m.createTableIfNotExists(ctx); err != nil {
+		return nil, gerror.Wrapf(err, `creating migration table "%s" failed`, m.in.Table)
+	}
+	result, err := m.db.Model(m.in.Table).Ctx(ctx).OrderAsc("version").All()
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[int64]*migrationRecord, len(result))
+	for _, item := range result {
+		record := &migrationRecord{
+			Version: item["version"].Int64(),
+			Name:    item["name"].String(),
+			Dirty:   item["dirty"].Bool(),
+		}
+		if !item["applied_at"].IsNil() {
+			record.AppliedAt = item["applied_at"].String()
+		}
+		records[record.Version] = record
+	}
+	return records, nil
+}
+
+// mustLoadRecords retrieves and returns all applied migration records,
+// it exits the process if any record is dirty, which needs fixing manually.
+func (m *migrator) mustLoadRecords(ctx context.Context) map[int64]*migrationRecord {
+	records, err := m.loadRecords(ctx)
+	if err != nil {
+		mlog.Fatalf(`retrieving migration records failed: %+v`, err)
+	}
+	for _, record := range records {
+		if record.Dirty {
+			mlog.Fatalf(
+				`database is dirty at migration version "%d", please fix it manually and use "gf migrate force" to set the version`,
+				record.Version,
+			)
+		}
+	}
+	return records
+}
+
+// apply applies the up sql of given migration.
+func (m *migrator) apply(ctx context.Context, file *migrationFile) error {
+	mlog.Printf(`applying migration: %d_%s`, file.Version, file.Name)
+	statements := splitSqlStatements(gfile.GetContents(file.UpPath))
+	if m.supportsTransactionalDDL() {
+		return m.db.Transaction(ctx, func(ctx context.Context, tx *gdb.TX) error {
+			if err := m.execStatements(ctx, tx, file.UpPath, statements); err != nil {
+				return err
+			}
+			_, err := tx.Model(m.in.Table).Ctx(ctx).Data(newMigrationRecordData(file, false)).Insert()
+			return err
+		})
+	}
+	// The record is marked dirty until all statements are successfully executed.
+	if _, err := m.db.Model(m.in.Table).Ctx(ctx).Data(newMigrationRecordData(file, true)).Insert(); err != nil {
+		return err
+	}
+	if err := m.execStatements(ctx, nil, file.UpPath, statements); err != nil {
+		return err
+	}
+	_, err := m.db.Model(m.in.Table).Ctx(ctx).Data(g.Map{"dirty": 0}).Where("version", file.Version).Update()
+	return err
+}
+
+// revert reverts the applied migration using its down sql.
+func (m *migrator) revert(ctx context.Context, file *migrationFile) error {
+	mlog.Printf(`reverting migration: %d_%s`, file.Version, file.Name)
+	statements := splitSqlStatements(gfile.GetContents(file.DownPath))
+	if m.supportsTransactionalDDL() {
+		return m.db.Transaction(ctx, func(ctx context.Context, tx *gdb.TX) error {
+			if err := m.execStatements(ctx, tx, file.DownPath, statements); err != nil {
+				return err
+			}
+			_, err := tx.Model(m.in.Table).Ctx(ctx).Where("version", file.Version).Delete()
+			return err
+		})
+	}
+	// The record is marked dirty until all statements are successfully executed.
+	if _, err := m.db.Model(m.in.Table).Ctx(ctx).Data(g.Map{"dirty": 1}).Where("version", file.Version).Update(); err != nil {
+		return err
+	}
+	if err := m.execStatements(ctx, nil, file.DownPath, statements); err != nil {
+		return err
+	}
+	_, err := m.db.Model(m.in.Table).Ctx(ctx).Where("version", file.Version).Delete()
+	return err
+}
+
+// execStatements executes given sql statements using transaction `tx` if it is not nil.
+func (m *migrator) execStatements(ctx context.Context, tx *gdb.TX, path string, statements []string) (err error) {
+	for _, statement := range statements {
+		if tx != nil {
+			_, err = tx.Ctx(ctx).Exec(statement)
+		} else {
+			_, err = m.db.Exec(ctx, statement)
+		}
+		if err != nil {
+			return gerror.Wrapf(err, `executing sql of migration file "%s" failed`, path)
+		}
+	}
+	return nil
+}
+
+// writeMigrationFiles creates a new pair of migration files with given name and contents in folder `path`.
+func writeMigrationFiles(path, name, upContent, downContent string) {
+	name = gstr.CaseSnake(gstr.Trim(name))
+	if name == "" || !gregex.IsMatchString(`^\w+$`, name) {
+		mlog.Fatalf(`invalid migration name "%s", only letters, numbers and "_" are allowed`, name)
+	}
+	var (
+		version  = gtime.Now().Format(migrationVersionLayout)
+		upPath   = gfile.Join(path, fmt.Sprintf(`%s_%s%s`, version, name, migrationFileUpSuffix))
+		downPath = gfile.Join(path, fmt.Sprintf(`%s_%s%s`, version, name, migrationFileDownSuffix))
+	)
+	for i, filePath := range []string{upPath, downPath} {
+		if gfile.Exists(filePath) {
+			mlog.Fatalf(`migration file "%s" already exists`, filePath)
+		}
+		if err := gfile.PutContents(filePath, []string{upContent, downContent}[i]); err != nil {
+			mlog.Fatalf("writing content to '%s' failed: %v", filePath, err)
+		}
+		mlog.Print("generated:", filePath)
+	}
+}
+
+// newMigrationRecordData creates and returns the record data of migration table for given migration.
+func newMigrationRecordData(file *migrationFile, dirty bool) g.Map {
+	return g.Map{
+		"version":    file.Version,
+		"name":       file.Name,
+		"dirty":      gconv.Int(dirty),
+		"applied_at": gtime.Now().String(),
+	}
+}
+
+// runGenDaoAfterMigration runs the "gen dao" command using the same database of migration.
+func runGenDaoAfterMigration(ctx context.Context, m *migrator) {
+	mlog.Print("generating dao files after migration...")
+	// The configuration of "gen dao" has the priority over the database of migration.
+	daoInput, err := getGenDaoInput(ctx, g.Map{
+		"link":  m.in.Link,
+		"group": m.in.Group,
+	})
+	if err != nil {
+		mlog.Fatalf(`invalid configuration of "%s": %+v`, cGenDaoConfig, err)
+	}
+	if _, err = Gen.Dao(ctx, daoInput); err != nil {
+		mlog.Fatal(err)
+	}
+}
+
+// splitSqlStatements splits the sql content into single statements by ';',
+// ignoring the separators in quoted strings and comments.
+// The separator can be changed by "DELIMITER" command of MySQL client,
+// so that the routines and triggers containing ';' can be defined, like:
+//
+//	DELIMITER //
+//	CREATE PROCEDURE p() BEGIN SELECT 1; END//
+//	DELIMITER ;
+func splitSqlStatements(content string) []string {
+	var (
+		statements = make([]string, 0)
+		buffer     = bytes.NewBuffer(nil)
+		delimiter  = ";"  // Current statement separator.
+		quote      byte   // Current quote char, it's 0 if not in quoted string.
+		dollarTag  string // Current dollar-quoted tag of PostgreSQL, like "$$" or "$body$".
+		length     = len(content)
+	)
+	for i := 0; i < length; i++ {
+		char := content[i]
+		switch {
+		case dollarTag != "":
+			if gstr.HasPrefix(content[i:], dollarTag) {
+				buffer.WriteString(dollarTag)
+				i += len(dollarTag) - 1
+				dollarTag = ""
+				continue
+			}
+
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else if char == '\\' && quote != '`' && i+1 < length {
+				buffer.WriteByte(char)
+				i++
+				char = content[i]
+			}
+
+		case (i == 0 || content[i-1] == '\n') && gstr.Trim(buffer.String()) == "" &&
+			gregex.IsMatchString(`^(?i)DELIMITER[ \t]+\S`, content[i:]):
+			// Delimiter command, which takes the whole line.
+			end := gstr.Pos(content[i:], "\n")
+			if end == -1 {
+				end = length - i
+			}
+			delimiter = gstr.Fields(content[i : i+end])[1]
+			i += end
+			continue
+
+		case char == '\'' || char == '"' || char == '`':
+			quote = char
+
+		case char == '$':
+			if match, _ := gregex.MatchString(`^\$\w*\$`, content[i:]); len(match) > 0 {
+				dollarTag = match[0]
+				buffer.WriteString(dollarTag)
+				i += len(dollarTag) - 1
+				continue
+			}
+
+		case char == '#' || (char == '-' && i+1 < length && content[i+1] == '-'):
+			// Line comment, the "#" one is of MySQL.
+			for i < length && content[i] != '\n' {
+				i++
+			}
+			buffer.WriteByte('\n')
+			continue
+
+		case char == '/' && i+1 < length && content[i+1] == '*':
+			// Block comment.
+			end := gstr.Pos(content[i+2:], "*/")
+			if end == -1 {
+				i = length
+			} else {
+				i += end + 3
+			}
+			buffer.WriteByte(' ')
+			continue
+
+		case gstr.HasPrefix(content[i:], delimiter):
+			if statement := gstr.Trim(buffer.String()); statement != "" {
+				statements = append(statements, statement)
+			}
+			buffer.Reset()
+			i += len(delimiter) - 1
+			continue
+		}
+		buffer.WriteByte(char)
+	}
+	if statement := gstr.Trim(buffer.String()); statement != "" {
+		statements = append(statements, statement)
+	}
+	return statements
+}
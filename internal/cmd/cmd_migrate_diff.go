@@ -0,0 +1,1117 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf-cli/v2/utility/mlog"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gtag"
+)
+
+const (
+	cMigrateDiffBrief = `generate migration files by comparing the database schema with a target schema`
+	cMigrateDiffEg    = `
+gf migrate diff add_user_avatar -f ./manifest/sql/schema.sql
+gf migrate diff -f ./manifest/migration/schema.json
+gf migrate diff -k "mysql:root:12345678@tcp(127.0.0.1:3306)/test_latest"
+gf migrate diff -f schema.sql -s "user_*,order" -e "user_log"
+`
+	cMigrateDiffAd = `
+TARGET SUPPORT
+    The target schema, which is the schema expected after migrating, can be one of:
+    1. a schema snapshot file in json/yaml/toml format, which is created by "gf migrate snapshot";
+    2. a sql file containing DDL statements like "CREATE TABLE", "CREATE INDEX", "COMMENT ON"
+       and "ALTER TABLE ... ADD FOREIGN KEY";
+    3. another database link.
+    The up sql migrates the database to the target schema, and the down sql reverts it,
+    both of which are generated in the dialect of the migrated database.
+`
+	cMigrateSnapshotBrief = `save the database schema to a snapshot file, which can be used as the target of "gf migrate diff"`
+	cMigrateSnapshotEg    = `
+gf migrate snapshot ./manifest/migration/schema.json
+gf migrate snapshot schema.json -s "user_*,order"
+`
+	cMigrateBriefDiffName   = `name for the generated migration, default is "schema_diff"`
+	cMigrateBriefTables     = `only the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cMigrateBriefTablesEx   = `excluding the given tables, multiple table patterns separated with ',', wildcard char '*' is supported`
+	cMigrateBriefTarget     = `target schema file, which is a snapshot file or a sql file containing DDL statements`
+	cMigrateBriefTargetLink = `target database configuration, the same as the ORM configuration of GoFrame`
+	cMigrateBriefFile       = `file path for saving the schema snapshot in json format`
+)
+
+func init() {
+	gtag.Sets(g.MapStrStr{
+		`cMigrateDiffBrief`:       cMigrateDiffBrief,
+		`cMigrateDiffEg`:          cMigrateDiffEg,
+		`cMigrateDiffAd`:          cMigrateDiffAd,
+		`cMigrateSnapshotBrief`:   cMigrateSnapshotBrief,
+		`cMigrateSnapshotEg`:      cMigrateSnapshotEg,
+		`cMigrateBriefDiffName`:   cMigrateBriefDiffName,
+		`cMigrateBriefTables`:     cMigrateBriefTables,
+		`cMigrateBriefTablesEx`:   cMigrateBriefTablesEx,
+		`cMigrateBriefTarget`:     cMigrateBriefTarget,
+		`cMigrateBriefTargetLink`: cMigrateBriefTargetLink,
+		`cMigrateBriefFile`:       cMigrateBriefFile,
+	})
+}
+
+type (
+	cMigrateDiffInput struct {
+		g.Meta     `name:"diff" brief:"{cMigrateDiffBrief}" eg:"{cMigrateDiffEg}" ad:"{cMigrateDiffAd}"`
+		Name       string `name:"NAME"       arg:"true" brief:"{cMigrateBriefDiffName}"`
+		Path       string `name:"path"       short:"p"  brief:"{cMigrateBriefPath}"`
+		Link       string `name:"link"       short:"l"  brief:"{cMigrateBriefLink}"`
+		Group      string `name:"group"      short:"g"  brief:"{cMigrateBriefGroup}"`
+		Table      string `name:"table"      short:"t"  brief:"{cMigrateBriefTable}"`
+		Tables     string `name:"tables"     short:"s"  brief:"{cMigrateBriefTables}"`
+		TablesEx   string `name:"tablesEx"   short:"e"  brief:"{cMigrateBriefTablesEx}"`
+		Target     string `name:"target"     short:"f"  brief:"{cMigrateBriefTarget}"`
+		TargetLink string `name:"targetLink" short:"k"  brief:"{cMigrateBriefTargetLink}"`
+	}
+	cMigrateDiffOutput struct{}
+
+	cMigrateSnapshotInput struct {
+		g.Meta   `name:"snapshot" brief:"{cMigrateSnapshotBrief}" eg:"{cMigrateSnapshotEg}"`
+		File     string `name:"FILE"     arg:"true" v:"required" brief:"{cMigrateBriefFile}"`
+		Link     string `name:"link"     short:"l"  brief:"{cMigrateBriefLink}"`
+		Group    string `name:"group"    short:"g"  brief:"{cMigrateBriefGroup}"`
+		Table    string `name:"table"    short:"t"  brief:"{cMigrateBriefTable}"`
+		Tables   string `name:"tables"   short:"s"  brief:"{cMigrateBriefTables}"`
+		TablesEx string `name:"tablesEx" short:"e"  brief:"{cMigrateBriefTablesEx}"`
+	}
+	cMigrateSnapshotOutput struct{}
+)
+
+func (c cMigrate) Diff(ctx context.Context, in cMigrateDiffInput) (out *cMigrateDiffOutput, err error) {
+	if (in.Target == "") == (in.TargetLink == "") {
+		mlog.Fatal(`either option "target" or "targetLink" should be given`)
+	}
+	if in.Name == "" {
+		in.Name = "schema_diff"
+	}
+	var (
+		m = newMigrator(ctx, cMigrateCommonInput{
+			Path:  in.Path,
+			Link:  in.Link,
+			Group: in.Group,
+			Table: in.Table,
+		}, false)
+		currentTables = m.mustLoadSchema(ctx, m.db, in.Tables, in.TablesEx)
+		targetTables  []*schemaTable
+	)
+	if in.TargetLink != "" {
+		targetDb := getDatabase(in.TargetLink, "")
+		if targetDb == nil {
+			mlog.Fatal("target database initialization failed")
+		}
+		targetTables = m.mustLoadSchema(ctx, targetDb, in.Tables, in.TablesEx)
+	} else {
+		if targetTables, err = loadSchemaFromFile(in.Target); err != nil {
+			mlog.Fatal(err)
+		}
+		targetTables = filterSchemaTables(targetTables, in.Tables, in.TablesEx, m.in.Table)
+	}
+	var (
+		differ         = newSchemaDiffer(m.db)
+		upStatements   = differ.Diff(currentTables, targetTables)
+		downStatements = differ.Diff(targetTables, currentTables)
+	)
+	if len(upStatements) == 0 {
+		mlog.Print("no schema difference found")
+		return
+	}
+	writeMigrationFiles(
+		m.in.Path, in.Name,
+		formatMigrationStatements(upStatements),
+		formatMigrationStatements(downStatements),
+	)
+	mlog.Print("please review the generated migration files before applying them")
+	mlog.Print("done!")
+	return
+}
+
+func (c cMigrate) Snapshot(ctx context.Context, in cMigrateSnapshotInput) (out *cMigrateSnapshotOutput, err error) {
+	var (
+		m = newMigrator(ctx, cMigrateCommonInput{
+			Link:  in.Link,
+			Group: in.Group,
+			Table: in.Table,
+		}, false)
+		tables = m.mustLoadSchema(ctx, m.db, in.Tables, in.TablesEx)
+	)
+	if err = saveSchemaSnapshot(in.File, tables); err != nil {
+		mlog.Fatalf("writing content to '%s' failed: %v", in.File, err)
+	}
+	mlog.Print("generated:", in.File)
+	mlog.Print("done!")
+	return
+}
+
+// mustLoadSchema retrieves and returns the table definitions of `db` that match given table patterns,
+// excluding the migration table.
+func (m *migrator) mustLoadSchema(ctx context.Context, db gdb.DB, tables, tablesEx string) []*schemaTable {
+	tableNames, err := db.Tables(ctx)
+	if err != nil {
+		mlog.Fatalf("fetching tables failed: \n %v", err)
+	}
+	tableNames = filterTableNames(tableNames, tables, tablesEx)
+	for i, tableName := range tableNames {
+		if tableName == m.in.Table {
+			tableNames = append(tableNames[:i], tableNames[i+1:]...)
+			break
+		}
+	}
+	schemaTables, err := loadDatabaseSchema(ctx, db, tableNames)
+	if err != nil {
+		mlog.Fatal(err)
+	}
+	return schemaTables
+}
+
+// filterSchemaTables filters the table definitions using table patterns, also removes the migration table.
+func filterSchemaTables(tables []*schemaTable, patterns, patternsEx, migrationTable string) []*schemaTable {
+	var (
+		names  = make([]string, 0, len(tables))
+		result = make([]*schemaTable, 0, len(tables))
+	)
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	names = filterTableNames(names, patterns, patternsEx)
+	for _, table := range tables {
+		if table.Name != migrationTable && gstr.InArray(names, table.Name) {
+			result = append(result, table)
+		}
+	}
+	return result
+}
+
+// formatMigrationStatements formats the statements as content of migration file.
+func formatMigrationStatements(statements []string) string {
+	buffer := bytes.NewBuffer(nil)
+	buffer.WriteString(fmt.Sprintf("-- Generated by \"gf migrate diff\" at %s.\n\n", gtime.Now().String()))
+	for _, statement := range statements {
+		if gstr.HasPrefix(statement, "--") {
+			buffer.WriteString(statement + "\n")
+		} else {
+			buffer.WriteString(statement + ";\n")
+		}
+	}
+	return buffer.String()
+}
+
+// loadSchemaFromFile loads table definitions from schema snapshot file or DDL sql file.
+func loadSchemaFromFile(path string) ([]*schemaTable, error) {
+	if gfile.ExtName(path) != "sql" {
+		return loadSchemaSnapshot(path)
+	}
+	if !gfile.Exists(path) {
+		return nil, gerror.Newf(`target sql file "%s" does not exist`, path)
+	}
+	return parseSchemaFromDDL(gfile.GetContents(path))
+}
+
+// parseSchemaFromDDL parses and returns table definitions from DDL statements.
+// It supports statements "CREATE TABLE", "CREATE INDEX", "COMMENT ON" and "ALTER TABLE ... ADD FOREIGN KEY",
+// others are ignored.
+func parseSchemaFromDDL(content string) ([]*schemaTable, error) {
+	var (
+		tables   = make([]*schemaTable, 0)
+		tableMap = make(map[string]*schemaTable)
+	)
+	for _, statement := range splitSqlStatements(content) {
+		statement = gstr.Trim(statement)
+		var (
+			match, _ = gregex.MatchString(
+				`(?is)^CREATE\s+(?:TEMPORARY\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([^\s(]+)\s*\((.+)\)([^)]*)$`,
+				statement,
+			)
+		)
+		if len(match) == 4 {
+			table, err := parseCreateTableStatement(match[1], match[2], match[3])
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, table)
+			tableMap[table.Name] = table
+			continue
+		}
+		// Index creating.
+		match, _ = gregex.MatchString(
+			`(?is)^CREATE\s+(UNIQUE\s+)?INDEX\s+(?:IF\s+NOT\s+EXISTS\s+)?(\S+)\s+ON\s+([^\s(]+)\s*(?:USING\s+\w+\s*)?\((.+)\)$`,
+			statement,
+		)
+		if len(match) == 5 {
+			table, ok := tableMap[unquoteSqlName(match[3])]
+			if !ok {
+				return nil, gerror.Newf(`table "%s" should be created before its index "%s"`, match[3], match[2])
+			}
+			table.Indexes = append(table.Indexes, &schemaIndex{
+				Name:    unquoteSqlName(match[2]),
+				Columns: parseIndexColumns(match[4]),
+				Unique:  match[1] != "",
+			})
+			continue
+		}
+		// Foreign key adding, which is how pg_dump outputs foreign keys.
+		match, _ = gregex.MatchString(`(?is)^ALTER\s+TABLE\s+(?:ONLY\s+)?(\S+)\s+ADD\s+(.+)$`, statement)
+		if len(match) == 3 {
+			if foreignKey := parseForeignKeyDefinition(match[2]); foreignKey != nil {
+				table, ok := tableMap[unquoteSqlName(match[1])]
+				if !ok {
+					return nil, gerror.Newf(`table "%s" should be created before its foreign key`, match[1])
+				}
+				table.ForeignKeys = append(table.ForeignKeys, foreignKey)
+				continue
+			}
+		}
+		// Comment of table or column.
+		match, _ = gregex.MatchString(`(?is)^COMMENT\s+ON\s+(TABLE|COLUMN)\s+(\S+)\s+IS\s+'((?:[^']|'')*)'$`, statement)
+		if len(match) == 4 {
+			var (
+				comment = gstr.Replace(match[3], `''`, `'`)
+				names   = gstr.Split(match[2], ".")
+			)
+			if gstr.Equal(match[1], "table") {
+				if table, ok := tableMap[unquoteSqlName(match[2])]; ok {
+					table.Comment = comment
+				}
+			} else if len(names) >= 2 {
+				if table, ok := tableMap[unquoteSqlName(names[len(names)-2])]; ok {
+					if column := table.Column(unquoteSqlName(names[len(names)-1])); column != nil {
+						column.Comment = comment
+					}
+				}
+			}
+			continue
+		}
+		mlog.Debugf(`ignore unsupported DDL statement: %s`, statement)
+	}
+	return tables, nil
+}
+
+// parseCreateTableStatement parses the "CREATE TABLE" statement parts.
+func parseCreateTableStatement(name, body, options string) (*schemaTable, error) {
+	table := &schemaTable{
+		Name:    unquoteSqlName(name),
+		Columns: make([]*schemaColumn, 0),
+	}
+	if match, _ := gregex.MatchString(`(?is)COMMENT\s*=?\s*'((?:[^']|'')*)'`, options); len(match) == 2 {
+		table.Comment = gstr.Replace(match[1], `''`, `'`)
+	}
+	for _, definition := range splitSqlTopLevel(body, ',') {
+		definition = gstr.Trim(definition)
+		var (
+			upper    = gstr.ToUpper(definition)
+			match, _ = gregex.MatchString(`(?is)^(?:CONSTRAINT\s+(\S+)\s+)?(PRIMARY\s+KEY|UNIQUE(?:\s+KEY|\s+INDEX)?|KEY|INDEX|FULLTEXT(?:\s+KEY|\s+INDEX)?|SPATIAL(?:\s+KEY|\s+INDEX)?)\s*(\S*?)\s*(?:USING\s+\w+\s*)?\((.+)\)`, definition)
+		)
+		if foreignKey := parseForeignKeyDefinition(definition); foreignKey != nil {
+			table.ForeignKeys = append(table.ForeignKeys, foreignKey)
+			continue
+		}
+		switch {
+		case len(match) == 5:
+			var (
+				kind  = gstr.ToUpper(match[2])
+				index = &schemaIndex{
+					Name:    unquoteSqlName(match[3]),
+					Columns: parseIndexColumns(match[4]),
+					Primary: gstr.HasPrefix(kind, "PRIMARY"),
+					Unique:  gstr.HasPrefix(kind, "PRIMARY") || gstr.HasPrefix(kind, "UNIQUE"),
+				}
+			)
+			if index.Name == "" {
+				index.Name = unquoteSqlName(match[1])
+			}
+			if index.Primary {
+				index.Name = "PRIMARY"
+			} else if index.Name == "" {
+				index.Name = gstr.Join(index.Columns, "_")
+			}
+			table.Indexes = append(table.Indexes, index)
+
+		case gstr.HasPrefix(upper, "CONSTRAINT"), gstr.HasPrefix(upper, "CHECK"):
+			mlog.Debugf(`ignore unsupported constraint of table "%s": %s`, table.Name, definition)
+
+		default:
+			column, inlineIndex, err := parseColumnDefinition(definition)
+			if err != nil {
+				return nil, gerror.Wrapf(err, `invalid definition of table "%s"`, table.Name)
+			}
+			table.Columns = append(table.Columns, column)
+			if inlineIndex != nil {
+				table.Indexes = append(table.Indexes, inlineIndex)
+			}
+		}
+	}
+	// Make sure the primary key is the first index.
+	for i, index := range table.Indexes {
+		if index.Primary && i > 0 {
+			table.Indexes = append(append([]*schemaIndex{index}, table.Indexes[:i]...), table.Indexes[i+1:]...)
+			break
+		}
+	}
+	return table, nil
+}
+
+// parseForeignKeyDefinition parses the foreign key definition like:
+// "CONSTRAINT fk_name FOREIGN KEY (a, b) REFERENCES t (c, d) ON DELETE CASCADE",
+// it returns nil if `definition` is not a foreign key definition.
+func parseForeignKeyDefinition(definition string) *schemaForeignKey {
+	match, _ := gregex.MatchString(
+		`(?is)^(?:CONSTRAINT\s+(\S+)\s+)?FOREIGN\s+KEY\s*(?:\S+?\s*)?\(([^)]+)\)\s*REFERENCES\s+([^\s(]+)\s*\(([^)]+)\)`,
+		gstr.Trim(definition),
+	)
+	if len(match) != 5 {
+		return nil
+	}
+	return &schemaForeignKey{
+		Name:              unquoteSqlName(match[1]),
+		Columns:           parseIndexColumns(match[2]),
+		ReferencedTable:   unquoteSqlName(match[3]),
+		ReferencedColumns: parseIndexColumns(match[4]),
+	}
+}
+
+// parseColumnDefinition parses the column definition in "CREATE TABLE" statement,
+// it also returns the index if the column is defined as "PRIMARY KEY" or "UNIQUE".
+func parseColumnDefinition(definition string) (column *schemaColumn, index *schemaIndex, err error) {
+	tokens := splitSqlTokens(definition)
+	if len(tokens) < 2 {
+		return nil, nil, gerror.Newf(`invalid column definition "%s"`, definition)
+	}
+	column = &schemaColumn{
+		Name: unquoteSqlName(tokens[0]),
+		Null: true,
+	}
+	// Column type, which might contain multiple words like "double precision", "int(10) unsigned".
+	var (
+		i         = 1
+		typeWords = make([]string, 0)
+	)
+	for ; i < len(tokens); i++ {
+		upper := gstr.ToUpper(tokens[i])
+		if i > 1 && isColumnConstraintKeyword(upper, tokens, i) {
+			break
+		}
+		if gstr.HasPrefix(tokens[i], "(") && len(typeWords) > 0 {
+			typeWords[len(typeWords)-1] += tokens[i]
+		} else {
+			typeWords = append(typeWords, tokens[i])
+		}
+	}
+	column.Type = gstr.Join(typeWords, " ")
+	if gstr.HasSuffix(gstr.ToLower(typeWords[0]), "serial") {
+		column.Extra = "auto_increment"
+		column.Null = false
+	}
+	for ; i < len(tokens); i++ {
+		var (
+			upper = gstr.ToUpper(tokens[i])
+			next  = ""
+		)
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+		switch upper {
+		case "NOT":
+			if gstr.Equal(next, "null") {
+				column.Null = false
+				i++
+			}
+		case "NULL":
+			column.Null = true
+		case "DEFAULT":
+			if !gstr.Equal(next, "null") {
+				value := unquoteSqlValue(next)
+				column.Default = &value
+			}
+			i++
+		case "AUTO_INCREMENT", "AUTOINCREMENT", "IDENTITY":
+			column.Extra = gstr.Trim(column.Extra + " auto_increment")
+			column.Null = false
+		case "ON":
+			if gstr.Equal(next, "update") && i+2 < len(tokens) {
+				column.Extra = gstr.Trim(column.Extra + " on update " + tokens[i+2])
+				i += 2
+			}
+		case "COMMENT":
+			column.Comment = unquoteSqlValue(next)
+			i++
+		case "PRIMARY":
+			column.Null = false
+			index = &schemaIndex{Name: "PRIMARY", Columns: []string{column.Name}, Primary: true, Unique: true}
+		case "UNIQUE":
+			index = &schemaIndex{Name: column.Name, Columns: []string{column.Name}, Unique: true}
+		}
+	}
+	return
+}
+
+// isColumnConstraintKeyword checks whether the token at position `i` starts the constraint part
+// of column definition.
+func isColumnConstraintKeyword(upper string, tokens []string, i int) bool {
+	switch upper {
+	case "NOT", "NULL", "DEFAULT", "AUTO_INCREMENT", "AUTOINCREMENT", "IDENTITY", "COMMENT", "PRIMARY",
+		"UNIQUE", "KEY", "REFERENCES", "CHECK", "CONSTRAINT", "COLLATE", "CHARSET", "GENERATED", "ON":
+		return true
+	case "CHARACTER":
+		return i+1 < len(tokens) && gstr.Equal(tokens[i+1], "set")
+	}
+	return false
+}
+
+// parseIndexColumns parses the column names from index definition like: "`a`, b(10), c DESC".
+func parseIndexColumns(definition string) []string {
+	columns := make([]string, 0)
+	for _, item := range splitSqlTopLevel(definition, ',') {
+		tokens := splitSqlTokens(gstr.Trim(item))
+		if len(tokens) > 0 {
+			columns = append(columns, unquoteSqlName(tokens[0]))
+		}
+	}
+	return columns
+}
+
+// splitSqlTopLevel splits `content` by `separator` that is not in parentheses or quoted strings.
+func splitSqlTopLevel(content string, separator byte) []string {
+	var (
+		parts  = make([]string, 0)
+		depth  = 0
+		quote  byte
+		start  = 0
+		length = len(content)
+	)
+	for i := 0; i < length; i++ {
+		char := content[i]
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else if char == '\\' && i+1 < length {
+				i++
+			}
+		case char == '\'' || char == '"' || char == '`':
+			quote = char
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+		case char == separator && depth == 0:
+			parts = append(parts, content[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, content[start:])
+}
+
+// splitSqlTokens splits `content` into tokens by white spaces, in which the quoted strings and
+// parenthesized parts are kept as single token. Parenthesized part is a separate token
+// even if it follows a word, like: "varchar(45)" -> ["varchar", "(45)"].
+func splitSqlTokens(content string) []string {
+	var (
+		tokens = make([]string, 0)
+		buffer = bytes.NewBuffer(nil)
+		depth  = 0
+		quote  byte
+	)
+	flush := func() {
+		if buffer.Len() > 0 {
+			tokens = append(tokens, buffer.String())
+			buffer.Reset()
+		}
+	}
+	for i := 0; i < len(content); i++ {
+		char := content[i]
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case depth > 0:
+			if char == '(' {
+				depth++
+			} else if char == ')' {
+				depth--
+			}
+			if depth == 0 {
+				buffer.WriteByte(char)
+				flush()
+				continue
+			}
+		case char == '\'' || char == '"' || char == '`' || char == '[':
+			if char == '[' {
+				quote = ']'
+			} else {
+				quote = char
+			}
+		case char == '(':
+			// Function call like "now()" is kept as one token.
+			if buffer.Len() == 0 || !gregex.IsMatchString(`(?i)^(now|current_timestamp|nextval|uuid|gen_random_uuid|getdate)$`, buffer.String()) {
+				flush()
+			}
+			depth++
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			flush()
+			continue
+		}
+		buffer.WriteByte(char)
+	}
+	flush()
+	return tokens
+}
+
+// unquoteSqlName removes the identifier quotes and schema prefix of name.
+func unquoteSqlName(name string) string {
+	array := gstr.Split(gstr.Trim(name), ".")
+	return gstr.Trim(array[len(array)-1], "`\"[]")
+}
+
+// unquoteSqlValue removes the quotes of string value, or returns the value as it is if it's an expression.
+func unquoteSqlValue(value string) string {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return gstr.Replace(value[1:len(value)-1], `''`, `'`)
+	}
+	return value
+}
+
+// schemaDiffer generates the sql statements transforming a schema to another in certain database dialect.
+type schemaDiffer struct {
+	dbType    string // Database type, like: mysql, pgsql, mssql, sqlite, oracle.
+	charLeft  string // Left quote char of identifier.
+	charRight string // Right quote char of identifier.
+}
+
+// newSchemaDiffer creates and returns a schemaDiffer in the dialect of given database.
+func newSchemaDiffer(db gdb.DB) *schemaDiffer {
+	charLeft, charRight := db.GetChars()
+	return &schemaDiffer{
+		dbType:    db.GetConfig().Type,
+		charLeft:  charLeft,
+		charRight: charRight,
+	}
+}
+
+// Diff returns the sql statements that transform schema `from` to schema `to`.
+func (d *schemaDiffer) Diff(from, to []*schemaTable) []string {
+	var (
+		statements = make([]string, 0)
+		fromMap    = make(map[string]*schemaTable)
+		toMap      = make(map[string]*schemaTable)
+	)
+	for _, table := range from {
+		fromMap[table.Name] = table
+	}
+	for _, table := range to {
+		toMap[table.Name] = table
+	}
+	// Dropping removed or changed foreign keys, before the referenced columns might be changed.
+	for _, table := range to {
+		if fromTable, ok := fromMap[table.Name]; ok {
+			statements = append(statements, d.dropForeignKeys(fromTable, table)...)
+		}
+	}
+	for _, table := range to {
+		if fromTable, ok := fromMap[table.Name]; ok {
+			statements = append(statements, d.alterTable(fromTable, table)...)
+		} else {
+			statements = append(statements, d.createTable(table)...)
+		}
+	}
+	// Adding new or changed foreign keys, after all the referenced tables are created.
+	for _, table := range to {
+		statements = append(statements, d.addForeignKeys(fromMap[table.Name], table)...)
+	}
+	for _, table := range from {
+		if _, ok := toMap[table.Name]; !ok {
+			statements = append(statements, fmt.Sprintf(`DROP TABLE %s`, d.quote(table.Name)))
+		}
+	}
+	return statements
+}
+
+func (d *schemaDiffer) createTable(table *schemaTable) []string {
+	var (
+		statements  = make([]string, 0)
+		definitions = make([]string, 0)
+	)
+	for _, column := range table.Columns {
+		definitions = append(definitions, "    "+d.columnDefinition(column))
+	}
+	if primary := table.PrimaryKey(); primary != nil {
+		definitions = append(definitions, fmt.Sprintf("    PRIMARY KEY (%s)", d.quoteNames(primary.Columns)))
+	}
+	createStatement := fmt.Sprintf(
+		"CREATE TABLE %s (\n%s\n)", d.quote(table.Name), gstr.Join(definitions, ",\n"),
+	)
+	if table.Comment != "" && d.isMysql() {
+		createStatement += " COMMENT=" + d.quoteValue(table.Comment)
+	}
+	statements = append(statements, createStatement)
+	for _, index := range table.Indexes {
+		if !index.Primary {
+			statements = append(statements, d.createIndex(table.Name, index))
+		}
+	}
+	if !d.isMysql() {
+		if table.Comment != "" {
+			statements = append(statements, d.tableComment(table.Name, table.Comment)...)
+		}
+		for _, column := range table.Columns {
+			if column.Comment != "" {
+				statements = append(statements, d.columnComment(table.Name, column)...)
+			}
+		}
+	}
+	return statements
+}
+
+func (d *schemaDiffer) alterTable(from, to *schemaTable) []string {
+	var (
+		statements  = make([]string, 0)
+		tableName   = d.quote(to.Name)
+		fromPrimary = from.PrimaryKey()
+		toPrimary   = to.PrimaryKey()
+		primaryDiff = !isSameIndex(fromPrimary, toPrimary)
+	)
+	// Dropping removed or changed indexes.
+	if primaryDiff && fromPrimary != nil {
+		statements = append(statements, d.dropPrimaryKey(to.Name, fromPrimary))
+	}
+	for _, index := range from.Indexes {
+		if !index.Primary && !isSameIndex(index, to.Index(index.Name)) {
+			statements = append(statements, d.dropIndex(to.Name, index))
+		}
+	}
+	// Dropping removed columns.
+	for _, column := range from.Columns {
+		if to.Column(column.Name) == nil {
+			statements = append(statements, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN %s`, tableName, d.quote(column.Name)))
+		}
+	}
+	// Adding new columns and modifying changed columns.
+	for i, column := range to.Columns {
+		fromColumn := from.Column(column.Name)
+		if fromColumn == nil {
+			statements = append(statements, d.addColumn(to, i)...)
+			continue
+		}
+		statements = append(statements, d.modifyColumn(to.Name, fromColumn, column)...)
+	}
+	// Adding new or changed indexes.
+	if primaryDiff && toPrimary != nil {
+		statements = append(statements, fmt.Sprintf(
+			`ALTER TABLE %s ADD PRIMARY KEY (%s)`, tableName, d.quoteNames(toPrimary.Columns),
+		))
+	}
+	for _, index := range to.Indexes {
+		if !index.Primary && !isSameIndex(index, from.Index(index.Name)) {
+			statements = append(statements, d.createIndex(to.Name, index))
+		}
+	}
+	// Table comment.
+	if normalizeComment(from.Comment) != normalizeComment(to.Comment) {
+		statements = append(statements, d.tableComment(to.Name, to.Comment)...)
+	}
+	return statements
+}
+
+// dropForeignKeys returns the statements dropping the foreign keys of `from` that are not in `to`.
+func (d *schemaDiffer) dropForeignKeys(from, to *schemaTable) []string {
+	statements := make([]string, 0)
+	for _, foreignKey := range from.ForeignKeys {
+		if hasSameForeignKey(to, foreignKey) {
+			continue
+		}
+		switch {
+		case d.isMysql():
+			statements = append(statements, fmt.Sprintf(
+				`ALTER TABLE %s DROP FOREIGN KEY %s`, d.quote(to.Name), d.quote(foreignKey.Name),
+			))
+		case d.dbType == "sqlite":
+			statements = append(statements, fmt.Sprintf(
+				`-- TODO: foreign key %s of table %s is removed, which needs manual migration as sqlite does not support altering constraint`,
+				d.quote(foreignKey.Name), d.quote(to.Name),
+			))
+		default:
+			statements = append(statements, fmt.Sprintf(
+				`ALTER TABLE %s DROP CONSTRAINT %s`, d.quote(to.Name), d.quote(foreignKey.Name),
+			))
+		}
+	}
+	return statements
+}
+
+// addForeignKeys returns the statements adding the foreign keys of `to` that are not in `from`,
+// in which `from` is nil if the table is newly created.
+func (d *schemaDiffer) addForeignKeys(from, to *schemaTable) []string {
+	statements := make([]string, 0)
+	for _, foreignKey := range to.ForeignKeys {
+		if from != nil && hasSameForeignKey(from, foreignKey) {
+			continue
+		}
+		constraint := ""
+		if foreignKey.Name != "" {
+			constraint = "CONSTRAINT " + d.quote(foreignKey.Name) + " "
+		}
+		definition := fmt.Sprintf(
+			`%sFOREIGN KEY (%s) REFERENCES %s (%s)`, constraint, d.quoteNames(foreignKey.Columns),
+			d.quote(foreignKey.ReferencedTable), d.quoteNames(foreignKey.ReferencedColumns),
+		)
+		if d.dbType == "sqlite" {
+			statements = append(statements, fmt.Sprintf(
+				`-- TODO: foreign key of table %s is added, which needs manual migration as sqlite does not support altering constraint: %s`,
+				d.quote(to.Name), definition,
+			))
+			continue
+		}
+		statements = append(statements, fmt.Sprintf(`ALTER TABLE %s ADD %s`, d.quote(to.Name), definition))
+	}
+	return statements
+}
+
+func (d *schemaDiffer) addColumn(table *schemaTable, i int) []string {
+	var (
+		column     = table.Columns[i]
+		statements = make([]string, 0)
+	)
+	switch d.dbType {
+	case "mssql":
+		statements = append(statements, fmt.Sprintf(
+			`ALTER TABLE %s ADD %s`, d.quote(table.Name), d.columnDefinition(column),
+		))
+	case "oracle":
+		statements = append(statements, fmt.Sprintf(
+			`ALTER TABLE %s ADD (%s)`, d.quote(table.Name), d.columnDefinition(column),
+		))
+	default:
+		statement := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s`, d.quote(table.Name), d.columnDefinition(column))
+		// Keep the column position for MySQL.
+		if d.isMysql() {
+			if i == 0 {
+				statement += " FIRST"
+			} else {
+				statement += " AFTER " + d.quote(table.Columns[i-1].Name)
+			}
+		}
+		statements = append(statements, statement)
+	}
+	if column.Comment != "" && !d.isMysql() {
+		statements = append(statements, d.columnComment(table.Name, column)...)
+	}
+	return statements
+}
+
+func (d *schemaDiffer) modifyColumn(tableName string, from, to *schemaColumn) []string {
+	var (
+		statements     = make([]string, 0)
+		typeChanged    = normalizeColumnType(from.Type) != normalizeColumnType(to.Type)
+		nullChanged    = from.Null != to.Null
+		defaultChanged = normalizeColumnDefault(from.Default) != normalizeColumnDefault(to.Default)
+		extraChanged   = normalizeColumnExtra(from.Extra) != normalizeColumnExtra(to.Extra)
+		commentChanged = normalizeComment(from.Comment) != normalizeComment(to.Comment)
+		quotedTable    = d.quote(tableName)
+		quotedColumn   = d.quote(to.Name)
+	)
+	if !typeChanged && !nullChanged && !defaultChanged && !extraChanged && !commentChanged {
+		return statements
+	}
+	switch d.dbType {
+	case "mysql", "mariadb", "tidb":
+		statements = append(statements, fmt.Sprintf(
+			`ALTER TABLE %s MODIFY COLUMN %s`, quotedTable, d.columnDefinition(to),
+		))
+
+	case "pgsql":
+		if typeChanged {
+			// The serial types are not real types, which cannot be used in altering.
+			columnType, _ := gregex.ReplaceString(`(?i)^(small|big)?serial$`, `${1}int`, to.Type)
+			columnType, _ = gregex.ReplaceString(`(?i)^int$`, `integer`, columnType)
+			statements = append(statements, fmt.Sprintf(
+				`ALTER TABLE %s ALTER COLUMN %s TYPE %s`, quotedTable, quotedColumn, columnType,
+			))
+		}
+		if nullChanged {
+			action := "SET NOT NULL"
+			if to.Null {
+				action = "DROP NOT NULL"
+			}
+			statements = append(statements, fmt.Sprintf(
+				`ALTER TABLE %s ALTER COLUMN %s %s`, quotedTable, quotedColumn, action,
+			))
+		}
+		if defaultChanged {
+			action := "DROP DEFAULT"
+			if normalizeColumnDefault(to.Default) != "<nil>" {
+				action = "SET DEFAULT " + d.defaultExpression(*to.Default)
+			}
+			statements = append(statements, fmt.Sprintf(
+				`ALTER TABLE %s ALTER COLUMN %s %s`, quotedTable, quotedColumn, action,
+			))
+		}
+		if commentChanged {
+			statements = append(statements, d.columnComment(tableName, to)...)
+		}
+
+	case "mssql":
+		if typeChanged || nullChanged {
+			statements = append(statements, fmt.Sprintf(
+				`ALTER TABLE %s ALTER COLUMN %s %s %s`, quotedTable, quotedColumn, to.Type, d.nullDefinition(to),
+			))
+		}
+		if defaultChanged || extraChanged {
+			statements = append(statements, fmt.Sprintf(
+				`-- TODO: the default value or identity of column %s.%s changed, which needs manual migration`,
+				quotedTable, quotedColumn,
+			))
+		}
+
+	case "oracle":
+		if typeChanged || nullChanged || defaultChanged {
+			definition := fmt.Sprintf(`%s %s`, quotedColumn, to.Type)
+			if normalizeColumnDefault(to.Default) != "<nil>" {
+				definition += " DEFAULT " + d.defaultExpression(*to.Default)
+			}
+			if nullChanged {
+				definition += " " + d.nullDefinition(to)
+			}
+			statements = append(statements, fmt.Sprintf(`ALTER TABLE %s MODIFY (%s)`, quotedTable, definition))
+		}
+		if commentChanged {
+			statements = append(statements, d.columnComment(tableName, to)...)
+		}
+
+	default:
+		statements = append(statements, fmt.Sprintf(
+			`-- TODO: column %s.%s changed, which needs manual migration as %s does not support altering column: %s`,
+			quotedTable, quotedColumn, d.dbType, d.columnDefinition(to),
+		))
+	}
+	return statements
+}
+
+// columnDefinition returns the column definition used in creating table or adding/modifying column.
+func (d *schemaDiffer) columnDefinition(column *schemaColumn) string {
+	var (
+		columnType      = column.Type
+		isAutoIncrement = gstr.Contains(normalizeColumnExtra(column.Extra), "auto_increment")
+	)
+	if isAutoIncrement && d.dbType == "pgsql" && !gstr.HasSuffix(gstr.ToLower(columnType), "serial") {
+		if gstr.ContainsI(columnType, "big") || gstr.ContainsI(columnType, "int8") {
+			columnType = "BIGSERIAL"
+		} else {
+			columnType = "SERIAL"
+		}
+	}
+	definition := fmt.Sprintf(`%s %s %s`, d.quote(column.Name), columnType, d.nullDefinition(column))
+	if normalizeColumnDefault(column.Default) != "<nil>" && !(isAutoIncrement && d.dbType == "pgsql") {
+		definition += " DEFAULT " + d.defaultExpression(*column.Default)
+	}
+	if isAutoIncrement {
+		switch d.dbType {
+		case "mysql", "mariadb", "tidb":
+			definition += " AUTO_INCREMENT"
+		case "mssql":
+			definition += " IDENTITY(1,1)"
+		case "oracle":
+			definition += " GENERATED BY DEFAULT AS IDENTITY"
+		}
+	}
+	if d.isMysql() {
+		if match, _ := gregex.MatchString(`(?i)on update (\S+)`, column.Extra); len(match) == 2 {
+			definition += " ON UPDATE " + match[1]
+		}
+		if column.Comment != "" {
+			definition += " COMMENT " + d.quoteValue(column.Comment)
+		}
+	}
+	return definition
+}
+
+func (d *schemaDiffer) nullDefinition(column *schemaColumn) string {
+	if column.Null {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+// defaultExpression returns the default value expression, which quotes the value if it's not a
+// number, keyword or function expression.
+func (d *schemaDiffer) defaultExpression(value string) string {
+	switch {
+	case gregex.IsMatchString(`^-?\d+(\.\d+)?$`, value),
+		gregex.IsMatchString(`(?i)^(null|true|false|current_timestamp|current_date|current_time|localtimestamp)$`, value),
+		gstr.Contains(value, "("),
+		gstr.Contains(value, "::"),
+		len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return value
+	}
+	return d.quoteValue(value)
+}
+
+func (d *schemaDiffer) createIndex(tableName string, index *schemaIndex) string {
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf(
+		`CREATE %sINDEX %s ON %s (%s)`,
+		unique, d.quote(index.Name), d.quote(tableName), d.quoteNames(index.Columns),
+	)
+}
+
+func (d *schemaDiffer) dropIndex(tableName string, index *schemaIndex) string {
+	switch d.dbType {
+	case "mysql", "mariadb", "tidb", "mssql":
+		return fmt.Sprintf(`DROP INDEX %s ON %s`, d.quote(index.Name), d.quote(tableName))
+	}
+	return fmt.Sprintf(`DROP INDEX %s`, d.quote(index.Name))
+}
+
+func (d *schemaDiffer) dropPrimaryKey(tableName string, index *schemaIndex) string {
+	if d.isMysql() {
+		return fmt.Sprintf(`ALTER TABLE %s DROP PRIMARY KEY`, d.quote(tableName))
+	}
+	name := index.Name
+	if name == "" || name == "PRIMARY" {
+		name = tableName + "_pkey"
+	}
+	return fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT %s`, d.quote(tableName), d.quote(name))
+}
+
+func (d *schemaDiffer) tableComment(tableName, comment string) []string {
+	switch d.dbType {
+	case "mysql", "mariadb", "tidb":
+		return []string{fmt.Sprintf(`ALTER TABLE %s COMMENT=%s`, d.quote(tableName), d.quoteValue(comment))}
+	case "pgsql", "oracle":
+		return []string{fmt.Sprintf(`COMMENT ON TABLE %s IS %s`, d.quote(tableName), d.commentValue(comment))}
+	}
+	return nil
+}
+
+func (d *schemaDiffer) columnComment(tableName string, column *schemaColumn) []string {
+	switch d.dbType {
+	case "pgsql", "oracle":
+		return []string{fmt.Sprintf(
+			`COMMENT ON COLUMN %s.%s IS %s`, d.quote(tableName), d.quote(column.Name), d.commentValue(column.Comment),
+		)}
+	}
+	return nil
+}
+
+// commentValue returns the quoted comment for "COMMENT ON" statement, which is NULL for removing the comment.
+func (d *schemaDiffer) commentValue(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return d.quoteValue(comment)
+}
+
+func (d *schemaDiffer) isMysql() bool {
+	switch d.dbType {
+	case "mysql", "mariadb", "tidb":
+		return true
+	}
+	return false
+}
+
+func (d *schemaDiffer) quote(name string) string {
+	return d.charLeft + name + d.charRight
+}
+
+func (d *schemaDiffer) quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = d.quote(name)
+	}
+	return gstr.Join(quoted, ", ")
+}
+
+func (d *schemaDiffer) quoteValue(value string) string {
+	if d.isMysql() {
+		value = gstr.Replace(value, `\`, `\\`)
+	}
+	return "'" + gstr.Replace(value, `'`, `''`) + "'"
+}
+
+// isSameIndex checks whether the two indexes have the same definition, the index names are not compared.
+func isSameIndex(a, b *schemaIndex) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Unique == b.Unique && gstr.Join(a.Columns, ",") == gstr.Join(b.Columns, ",")
+}
+
+// hasSameForeignKey checks whether `table` has foreign key of the same definition as `foreignKey`.
+// The foreign key names are not compared, as the names of foreign keys defined without name are generated
+// by database.
+func hasSameForeignKey(table *schemaTable, foreignKey *schemaForeignKey) bool {
+	for _, item := range table.ForeignKeys {
+		if gstr.Join(item.Columns, ",") == gstr.Join(foreignKey.Columns, ",") &&
+			item.ReferencedTable == foreignKey.ReferencedTable &&
+			gstr.Join(item.ReferencedColumns, ",") == gstr.Join(foreignKey.ReferencedColumns, ",") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeColumnType normalizes the column type for comparing,
+// which ignores the letter case, redundant white spaces and integer display width,
+// and maps the type aliases to the same name, like: "character varying(45)" -> "varchar(45)".
+func normalizeColumnType(columnType string) string {
+	columnType = gstr.ToLower(gstr.Trim(columnType))
+	columnType, _ = gregex.ReplaceString(`\s+`, ` `, columnType)
+	columnType, _ = gregex.ReplaceString(`\s*([(,])\s*`, `$1`, columnType)
+	columnType, _ = gregex.ReplaceString(`\s+\)`, `)`, columnType)
+	// MySQL reports "bool" and "boolean" as "tinyint(1)", whose display width is kept.
+	columnType, _ = gregex.ReplaceString(`^(bool|boolean)$`, `tinyint(1)`, columnType)
+	columnType, _ = gregex.ReplaceString(`^(tinyint\(1\))|^(smallint|mediumint|int|integer|bigint|tinyint)\(\d+\)`, `$1$2`, columnType)
+	columnType, _ = gregex.ReplaceString(`^character varying\b`, `varchar`, columnType)
+	columnType, _ = gregex.ReplaceString(`^character\b`, `char`, columnType)
+	columnType, _ = gregex.ReplaceString(`^(timestamp|time)(\(\d+\))? without time zone$`, `$1$2`, columnType)
+	columnType, _ = gregex.ReplaceString(`^(timestamp|time)(\(\d+\))? with time zone$`, `${1}tz$2`, columnType)
+	columnType, _ = gregex.ReplaceString(`^(double precision|float8)$`, `double`, columnType)
+	columnType, _ = gregex.ReplaceString(`^(real|float4)$`, `float`, columnType)
+	columnType, _ = gregex.ReplaceString(`^numeric\b`, `decimal`, columnType)
+	columnType, _ = gregex.ReplaceString(`^(integer|int4|serial)\b`, `int`, columnType)
+	columnType, _ = gregex.ReplaceString(`^(int8|bigserial)\b`, `bigint`, columnType)
+	columnType, _ = gregex.ReplaceString(`^(int2|smallserial)\b`, `smallint`, columnType)
+	return columnType
+}
+
+// normalizeColumnDefault normalizes the default value for comparing,
+// which removes the quotes and type cast of the value.
+func normalizeColumnDefault(value *string) string {
+	if value == nil || gstr.Equal(*value, "null") {
+		return "<nil>"
+	}
+	normalized, _ := gregex.ReplaceString(`::[\w\s]+(\[\])?$`, ``, gstr.Trim(*value))
+	normalized = unquoteSqlValue(normalized)
+	return strings.ToLower(normalized)
+}
+
+// normalizeColumnExtra normalizes the extra information for comparing,
+// which only cares about the auto increment and on update attributes.
+func normalizeColumnExtra(extra string) string {
+	var (
+		extraLower = gstr.ToLower(extra)
+		normalized = make([]string, 0)
+	)
+	if gstr.Contains(extraLower, "auto_increment") {
+		normalized = append(normalized, "auto_increment")
+	}
+	if match, _ := gregex.MatchString(`on update (\S+)`, extraLower); len(match) == 2 {
+		normalized = append(normalized, "on update "+gstr.TrimRight(match[1], "()"))
+	}
+	return gstr.Join(normalized, " ")
+}
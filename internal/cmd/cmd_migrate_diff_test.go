@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func Test_normalizeColumnType(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var cases = []struct {
+			from string
+			to   string
+		}{
+			{from: "INT(11)", to: "int"},
+			{from: "int(10)  unsigned", to: "int unsigned"},
+			{from: "integer", to: "int"},
+			{from: "serial", to: "int"},
+			{from: "int8", to: "bigint"},
+			{from: "bigint(20)", to: "bigint"},
+			{from: "tinyint(4)", to: "tinyint"},
+			{from: "tinyint(1)", to: "tinyint(1)"},
+			{from: "bool", to: "tinyint(1)"},
+			{from: "BOOLEAN", to: "tinyint(1)"},
+			{from: "character varying(45)", to: "varchar(45)"},
+			{from: "character(2)", to: "char(2)"},
+			{from: "timestamp without time zone", to: "timestamp"},
+			{from: "timestamp(6) without time zone", to: "timestamp(6)"},
+			{from: "timestamp with time zone", to: "timestamptz"},
+			{from: "time without time zone", to: "time"},
+			{from: "double precision", to: "double"},
+			{from: "float8", to: "double"},
+			{from: "real", to: "float"},
+			{from: "numeric(10, 2)", to: "decimal(10,2)"},
+			{from: "varchar(45)", to: "varchar(45)"},
+		}
+		for _, c := range cases {
+			t.Assert(normalizeColumnType(c.from), c.to)
+		}
+	})
+}
+
+func Test_schemaDiffer_Diff(t *testing.T) {
+	var (
+		mysql = &schemaDiffer{dbType: "mysql", charLeft: "`", charRight: "`"}
+		pgsql = &schemaDiffer{dbType: "pgsql", charLeft: `"`, charRight: `"`}
+	)
+	gtest.C(t, func(t *gtest.T) {
+		var cases = []struct {
+			name       string
+			differ     *schemaDiffer
+			from       string
+			to         string
+			statements []string
+		}{
+			{
+				name:   "mysql type aliases",
+				differ: mysql,
+				from:   "CREATE TABLE user (id int(11) NOT NULL, enabled tinyint(1) NOT NULL, PRIMARY KEY (id))",
+				to:     "CREATE TABLE user (id int NOT NULL, enabled bool NOT NULL, PRIMARY KEY (id))",
+			},
+			{
+				name:   "pgsql type aliases",
+				differ: pgsql,
+				from: "CREATE TABLE account (id integer NOT NULL, name character varying(45) NULL, " +
+					"score double precision NULL, created_at timestamp without time zone NULL, PRIMARY KEY (id))",
+				to: "CREATE TABLE account (id int NOT NULL, name varchar(45) NULL, " +
+					"score float8 NULL, created_at timestamp NULL, PRIMARY KEY (id))",
+			},
+			{
+				name:   "column type changed",
+				differ: mysql,
+				from:   "CREATE TABLE user (id int NOT NULL, name varchar(45) NULL)",
+				to:     "CREATE TABLE user (id int NOT NULL, name varchar(64) NULL)",
+				statements: []string{
+					"ALTER TABLE `user` MODIFY COLUMN `name` varchar(64) NULL",
+				},
+			},
+			{
+				name:   "mysql foreign key added",
+				differ: mysql,
+				from:   "CREATE TABLE user (id int NOT NULL); CREATE TABLE post (id int NOT NULL, user_id int NOT NULL)",
+				to: "CREATE TABLE user (id int NOT NULL); CREATE TABLE post (id int NOT NULL, user_id int NOT NULL, " +
+					"CONSTRAINT fk_post_user FOREIGN KEY (user_id) REFERENCES user (id) ON DELETE CASCADE)",
+				statements: []string{
+					"ALTER TABLE `post` ADD CONSTRAINT `fk_post_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)",
+				},
+			},
+			{
+				name:   "mysql foreign key dropped",
+				differ: mysql,
+				from: "CREATE TABLE user (id int NOT NULL); CREATE TABLE post (id int NOT NULL, user_id int NOT NULL, " +
+					"CONSTRAINT fk_post_user FOREIGN KEY (user_id) REFERENCES user (id))",
+				to: "CREATE TABLE user (id int NOT NULL); CREATE TABLE post (id int NOT NULL, user_id int NOT NULL)",
+				statements: []string{
+					"ALTER TABLE `post` DROP FOREIGN KEY `fk_post_user`",
+				},
+			},
+			{
+				name:   "pgsql foreign key changed",
+				differ: pgsql,
+				from: "CREATE TABLE post (id int NOT NULL, user_id int NOT NULL, author_id int NOT NULL, " +
+					"CONSTRAINT post_user_id_fkey FOREIGN KEY (user_id) REFERENCES account (id))",
+				to: "CREATE TABLE post (id int NOT NULL, user_id int NOT NULL, author_id int NOT NULL); " +
+					"ALTER TABLE ONLY public.post ADD CONSTRAINT post_author_id_fkey FOREIGN KEY (author_id) REFERENCES public.account(id)",
+				statements: []string{
+					`ALTER TABLE "post" DROP CONSTRAINT "post_user_id_fkey"`,
+					`ALTER TABLE "post" ADD CONSTRAINT "post_author_id_fkey" FOREIGN KEY ("author_id") REFERENCES "account" ("id")`,
+				},
+			},
+			{
+				name:   "foreign key renamed only",
+				differ: mysql,
+				from: "CREATE TABLE post (id int NOT NULL, user_id int NOT NULL, " +
+					"CONSTRAINT post_ibfk_1 FOREIGN KEY (user_id) REFERENCES user (id))",
+				to: "CREATE TABLE post (id int NOT NULL, user_id int NOT NULL, FOREIGN KEY (user_id) REFERENCES user (id))",
+			},
+			{
+				name:   "foreign key of created table",
+				differ: mysql,
+				from:   "",
+				to: "CREATE TABLE post (id int NOT NULL, user_id int NOT NULL, FOREIGN KEY (user_id) REFERENCES user (id)); " +
+					"CREATE TABLE user (id int NOT NULL)",
+				statements: []string{
+					"CREATE TABLE `post` (\n    `id` int NOT NULL,\n    `user_id` int NOT NULL\n)",
+					"CREATE TABLE `user` (\n    `id` int NOT NULL\n)",
+					"ALTER TABLE `post` ADD FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)",
+				},
+			},
+		}
+		for _, c := range cases {
+			from, err := parseSchemaFromDDL(c.from)
+			t.AssertNil(err)
+			to, err := parseSchemaFromDDL(c.to)
+			t.AssertNil(err)
+			statements := c.differ.Diff(from, to)
+			if c.statements == nil {
+				t.Assert(len(statements), 0)
+			} else {
+				t.Assert(statements, c.statements)
+			}
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func Test_splitSqlStatements(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var cases = []struct {
+			name       string
+			content    string
+			statements []string
+		}{
+			{
+				name:       "separator",
+				content:    "CREATE TABLE a (id int);\nINSERT INTO a VALUES (1) ;\n\n;",
+				statements: []string{"CREATE TABLE a (id int)", "INSERT INTO a VALUES (1)"},
+			},
+			{
+				name:       "no trailing separator",
+				content:    "SELECT 1; SELECT 2",
+				statements: []string{"SELECT 1", "SELECT 2"},
+			},
+			{
+				name:       "quotes",
+				content:    "INSERT INTO a VALUES ('a;b', \"c;d\"); SELECT `e;f` FROM a",
+				statements: []string{"INSERT INTO a VALUES ('a;b', \"c;d\")", "SELECT `e;f` FROM a"},
+			},
+			{
+				name:       "escapes",
+				content:    `INSERT INTO a VALUES ('it\'s;', "say \"hi;\""); SELECT 1`,
+				statements: []string{`INSERT INTO a VALUES ('it\'s;', "say \"hi;\"")`, "SELECT 1"},
+			},
+			{
+				name:    "dollar quotes",
+				content: "CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END $body$ LANGUAGE plpgsql; SELECT $$a;b$$",
+				statements: []string{
+					"CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END $body$ LANGUAGE plpgsql",
+					"SELECT $$a;b$$",
+				},
+			},
+			{
+				name:       "line comments",
+				content:    "-- don't; split\nSELECT 1; # don't; split\nSELECT 2",
+				statements: []string{"SELECT 1", "SELECT 2"},
+			},
+			{
+				name:       "block comments",
+				content:    "SELECT /* don't; split */ 1; /* ; */ SELECT 2",
+				statements: []string{"SELECT   1", "SELECT 2"},
+			},
+			{
+				name:       "comment chars in quotes",
+				content:    "INSERT INTO a VALUES ('#1', '--2', '/*3*/'); SELECT 1",
+				statements: []string{"INSERT INTO a VALUES ('#1', '--2', '/*3*/')", "SELECT 1"},
+			},
+			{
+				name: "delimiter",
+				content: "DELIMITER //\n" +
+					"CREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND//\n" +
+					"CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW BEGIN SET NEW.id = 1; END //\n" +
+					"delimiter ;\n" +
+					"SELECT 3;",
+				statements: []string{
+					"CREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND",
+					"CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW BEGIN SET NEW.id = 1; END",
+					"SELECT 3",
+				},
+			},
+			{
+				name:       "delimiter not at statement beginning",
+				content:    "SELECT 'DELIMITER //'; SELECT 1",
+				statements: []string{"SELECT 'DELIMITER //'", "SELECT 1"},
+			},
+		}
+		for _, c := range cases {
+			t.Assert(splitSqlStatements(c.content), c.statements)
+		}
+	})
+}
@@ -39,6 +39,7 @@ it enables packing SRC to go file, or else it packs SRC into a binary file.
 `
 	cPackNameBrief   = `package name for output go file, it's set as its directory name if no name passed`
 	cPackPrefixBrief = `prefix for each file packed into the resource file`
+	cPackForceBrief  = `overwrite the packed go file even if it was modified after last packing`
 )
 
 func init() {
@@ -50,6 +51,7 @@ func init() {
 		`cPackDstBrief`:    cPackDstBrief,
 		`cPackNameBrief`:   cPackNameBrief,
 		`cPackPrefixBrief`: cPackPrefixBrief,
+		`cPackForceBrief`:  cPackForceBrief,
 	})
 }
 
@@ -59,6 +61,7 @@ type cPackInput struct {
 	Dst    string `name:"DST" arg:"true" v:"required" brief:"{cPackDstBrief}"`
 	Name   string `name:"name"   short:"n" brief:"{cPackNameBrief}"`
 	Prefix string `name:"prefix" short:"p" brief:"{cPackPrefixBrief}"`
+	Force  bool   `name:"force"  short:"f" brief:"{cPackForceBrief}" orphan:"true"`
 }
 type cPackOutput struct{}
 
@@ -76,9 +79,16 @@ func (c cPack) Index(ctx context.Context, in cPackInput) (out *cPackOutput, err
 		in.Name = gfile.Basename(gfile.Dir(in.Dst))
 	}
 	if in.Name != "" {
+		// The packed go file is recorded in the manifest.
+		if err = checkGeneratedFile(in.Dst, in.Force); err != nil {
+			mlog.Fatalf("pack failed: %v", err)
+		}
 		if err = gres.PackToGoFile(in.Src, in.Dst, in.Name, in.Prefix); err != nil {
 			mlog.Fatalf("pack failed: %v", err)
 		}
+		beginGeneratedManifest(getGeneratedManifestScope("pack", in.Dst))
+		recordGeneratedFile(in.Dst)
+		endGeneratedManifest(true)
 	} else {
 		if err = gres.PackToFile(in.Src, in.Dst, in.Prefix); err != nil {
 			mlog.Fatalf("pack failed: %v", err)
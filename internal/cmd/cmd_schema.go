@@ -0,0 +1,436 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gogf/gf/v2/container/garray"
+	"github.com/gogf/gf/v2/container/gvar"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/text/gregex"
+	"github.com/gogf/gf/v2/text/gstr"
+	"github.com/gogf/gf/v2/util/gconv"
+)
+
+type (
+	// schemaTable is the structure definition of a database table,
+	// which is used for schema comparing and documenting purpose.
+	schemaTable struct {
+		Name    string          `json:"name"`              // Table name.
+		Comment string          `json:"comment,omitempty"` // Table comment.
+		Columns []*schemaColumn `json:"columns"`           // Columns ordered as they are defined in table.
+		Indexes []*schemaIndex  `json:"indexes,omitempty"` // Indexes including the primary key.
+		// ForeignKeys are the foreign key constraints referencing other tables.
+		ForeignKeys []*schemaForeignKey `json:"foreignKeys,omitempty"`
+	}
+	// schemaColumn is the structure definition of a table column.
+	schemaColumn struct {
+		Name    string  `json:"name"`              // Column name.
+		Type    string  `json:"type"`              // Column type in database dialect, like: int(10) unsigned, varchar(45).
+		Null    bool    `json:"null"`              // Column can be null or not.
+		Default *string `json:"default,omitempty"` // Default value expression, it's nil if the column has no default value.
+		Extra   string  `json:"extra,omitempty"`   // Extra information, like: auto_increment.
+		Comment string  `json:"comment,omitempty"` // Column comment.
+	}
+	// schemaIndex is the structure definition of a table index.
+	schemaIndex struct {
+		Name    string   `json:"name"`              // Index name.
+		Columns []string `json:"columns"`           // Indexed columns in order.
+		Unique  bool     `json:"unique,omitempty"`  // Unique index or not.
+		Primary bool     `json:"primary,omitempty"` // Primary key or not.
+	}
+	// schemaForeignKey is the structure definition of a foreign key constraint.
+	schemaForeignKey struct {
+		Name              string   `json:"name"`              // Constraint name.
+		Columns           []string `json:"columns"`           // Columns of current table.
+		ReferencedTable   string   `json:"referencedTable"`   // Referenced table name.
+		ReferencedColumns []string `json:"referencedColumns"` // Referenced columns of referenced table.
+	}
+)
+
+// Column returns the column of given name, or nil if not found.
+func (t *schemaTable) Column(name string) *schemaColumn {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// Index returns the index of given name, or nil if not found.
+func (t *schemaTable) Index(name string) *schemaIndex {
+	for _, index := range t.Indexes {
+		if index.Name == name {
+			return index
+		}
+	}
+	return nil
+}
+
+// PrimaryKey returns the primary key index of the table, or nil if the table has no primary key.
+func (t *schemaTable) PrimaryKey() *schemaIndex {
+	for _, index := range t.Indexes {
+		if index.Primary {
+			return index
+		}
+	}
+	return nil
+}
+
+// filterTableNames filters `tableNames` using table patterns `tables` and excluding patterns `tablesEx`.
+// The patterns are separated with ',' and support wildcard char '*', like: "user_*,order".
+func filterTableNames(tableNames []string, tables, tablesEx string) []string {
+	var (
+		includes = gstr.SplitAndTrim(tables, ",")
+		excludes = gstr.SplitAndTrim(tablesEx, ",")
+		array    = garray.NewStrArray()
+	)
+	for _, tableName := range tableNames {
+		if len(includes) > 0 && !matchTablePatterns(tableName, includes) {
+			continue
+		}
+		if matchTablePatterns(tableName, excludes) {
+			continue
+		}
+		array.Append(tableName)
+	}
+	return array.Slice()
+}
+
+// matchTablePatterns checks whether `tableName` matches any of `patterns`.
+func matchTablePatterns(tableName string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if !gstr.Contains(pattern, "*") {
+			if pattern == tableName {
+				return true
+			}
+			continue
+		}
+		expr := "^" + gstr.Replace(gregex.Quote(pattern), `\*`, `.*`) + "$"
+		if gregex.IsMatchString(expr, tableName) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadDatabaseSchema retrieves and returns the table definitions of given tables from database.
+func loadDatabaseSchema(ctx context.Context, db gdb.DB, tableNames []string) ([]*schemaTable, error) {
+	tables := make([]*schemaTable, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		table, err := loadTableSchema(ctx, db, tableName)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// loadTableSchema retrieves and returns the definition of given table from database.
+func loadTableSchema(ctx context.Context, db gdb.DB, tableName string) (*schemaTable, error) {
+	fieldMap, err := db.TableFields(ctx, tableName)
+	if err != nil {
+		return nil, gerror.Wrapf(err, `fetching fields failed for table "%s"`, tableName)
+	}
+	table := &schemaTable{
+		Name:    tableName,
+		Columns: make([]*schemaColumn, 0, len(fieldMap)),
+	}
+	for _, name := range sortFieldKeyForDao(fieldMap) {
+		field := fieldMap[name]
+		column := &schemaColumn{
+			Name:    field.Name,
+			Type:    field.Type,
+			Null:    field.Null,
+			Extra:   field.Extra,
+			Comment: field.Comment,
+		}
+		if field.Default != nil {
+			defaultValue := gconv.String(field.Default)
+			column.Default = &defaultValue
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	if table.Comment, err = loadTableComment(ctx, db, tableName); err != nil {
+		return nil, gerror.Wrapf(err, `fetching comment failed for table "%s"`, tableName)
+	}
+	if table.Indexes, err = loadTableIndexes(ctx, db, tableName); err != nil {
+		return nil, gerror.Wrapf(err, `fetching indexes failed for table "%s"`, tableName)
+	}
+	if table.ForeignKeys, err = loadTableForeignKeys(ctx, db, tableName); err != nil {
+		return nil, gerror.Wrapf(err, `fetching foreign keys failed for table "%s"`, tableName)
+	}
+	// Primary key from field definition if it is not introspected as index,
+	// like the database type does not support index introspection, or sqlite "INTEGER PRIMARY KEY".
+	if table.PrimaryKey() == nil {
+		primary := &schemaIndex{Name: "PRIMARY", Primary: true, Unique: true}
+		for _, name := range sortFieldKeyForDao(fieldMap) {
+			if gstr.ContainsI(fieldMap[name].Key, "pri") {
+				primary.Columns = append(primary.Columns, name)
+			}
+		}
+		if len(primary.Columns) > 0 {
+			table.Indexes = append([]*schemaIndex{primary}, table.Indexes...)
+		}
+	}
+	return table, nil
+}
+
+// loadTableComment retrieves and returns the comment of given table.
+func loadTableComment(ctx context.Context, db gdb.DB, tableName string) (string, error) {
+	var sql string
+	switch db.GetConfig().Type {
+	case "mysql", "mariadb", "tidb":
+		sql = `SELECT TABLE_COMMENT FROM information_schema.TABLES WHERE TABLE_SCHEMA=DATABASE() AND TABLE_NAME=?`
+	case "pgsql":
+		sql = `SELECT obj_description(c.oid, 'pg_class') FROM pg_class c WHERE c.relkind='r' AND c.relname=?`
+	case "mssql":
+		sql = `SELECT CAST(p.value AS NVARCHAR(4000)) FROM sys.extended_properties p ` +
+			`WHERE p.major_id=OBJECT_ID(?) AND p.minor_id=0 AND p.name='MS_Description'`
+	default:
+		return "", nil
+	}
+	value, err := db.GetValue(ctx, sql, tableName)
+	if err != nil {
+		return "", err
+	}
+	return normalizeComment(value.String()), nil
+}
+
+// loadTableIndexes retrieves and returns the indexes of given table, the primary key is the first one if any.
+func loadTableIndexes(ctx context.Context, db gdb.DB, tableName string) ([]*schemaIndex, error) {
+	var (
+		err    error
+		result gdb.Result
+		core   = db.GetCore()
+	)
+	// Each record of the result contains: index_name, column_name, is_unique, is_primary,
+	// ordered by index name and column sequence in index.
+	switch db.GetConfig().Type {
+	case "mysql", "mariadb", "tidb":
+		result, err = db.GetAll(ctx,
+			`SELECT INDEX_NAME AS index_name, COLUMN_NAME AS column_name, NON_UNIQUE=0 AS is_unique, `+
+				`INDEX_NAME='PRIMARY' AS is_primary FROM information_schema.STATISTICS `+
+				`WHERE TABLE_SCHEMA=DATABASE() AND TABLE_NAME=? ORDER BY INDEX_NAME, SEQ_IN_INDEX`,
+			tableName,
+		)
+
+	case "pgsql":
+		result, err = db.GetAll(ctx,
+			`SELECT i.relname AS index_name, a.attname AS column_name, ix.indisunique AS is_unique, `+
+				`ix.indisprimary AS is_primary FROM pg_class t `+
+				`JOIN pg_index ix ON t.oid=ix.indrelid JOIN pg_class i ON i.oid=ix.indexrelid `+
+				`JOIN pg_attribute a ON a.attrelid=t.oid AND a.attnum=ANY(ix.indkey) `+
+				`WHERE t.relkind='r' AND t.relname=? ORDER BY i.relname, array_position(ix.indkey::int2[], a.attnum)`,
+			tableName,
+		)
+
+	case "mssql":
+		result, err = db.GetAll(ctx,
+			`SELECT i.name AS index_name, c.name AS column_name, i.is_unique AS is_unique, `+
+				`i.is_primary_key AS is_primary FROM sys.indexes i `+
+				`JOIN sys.index_columns ic ON i.object_id=ic.object_id AND i.index_id=ic.index_id `+
+				`JOIN sys.columns c ON ic.object_id=c.object_id AND ic.column_id=c.column_id `+
+				`WHERE i.object_id=OBJECT_ID(?) ORDER BY i.name, ic.key_ordinal`,
+			tableName,
+		)
+
+	case "sqlite":
+		var list gdb.Result
+		list, err = db.GetAll(ctx, fmt.Sprintf(`PRAGMA index_list(%s)`, core.QuoteWord(tableName)))
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list {
+			var info gdb.Result
+			info, err = db.GetAll(ctx, fmt.Sprintf(`PRAGMA index_info(%s)`, core.QuoteWord(item["name"].String())))
+			if err != nil {
+				return nil, err
+			}
+			for _, column := range info {
+				result = append(result, gdb.Record{
+					"index_name":  item["name"],
+					"column_name": column["name"],
+					"is_unique":   item["unique"],
+					"is_primary":  gvar.New(item["origin"].String() == "pk"),
+				})
+			}
+		}
+
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var (
+		indexes  = make([]*schemaIndex, 0)
+		indexMap = make(map[string]*schemaIndex)
+	)
+	for _, item := range result {
+		name := item["index_name"].String()
+		index, ok := indexMap[name]
+		if !ok {
+			index = &schemaIndex{
+				Name:    name,
+				Unique:  item["is_unique"].Bool(),
+				Primary: item["is_primary"].Bool(),
+			}
+			indexMap[name] = index
+			indexes = append(indexes, index)
+		}
+		index.Columns = append(index.Columns, item["column_name"].String())
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return indexes[i].Primary && !indexes[j].Primary
+	})
+	return indexes, nil
+}
+
+// loadTableForeignKeys retrieves and returns the foreign keys of given table.
+func loadTableForeignKeys(ctx context.Context, db gdb.DB, tableName string) ([]*schemaForeignKey, error) {
+	var (
+		err    error
+		result gdb.Result
+	)
+	// Each record of the result contains: name, column_name, referenced_table, referenced_column,
+	// ordered by constraint name and column sequence in constraint.
+	switch db.GetConfig().Type {
+	case "mysql", "mariadb", "tidb":
+		result, err = db.GetAll(ctx,
+			`SELECT CONSTRAINT_NAME AS name, COLUMN_NAME AS column_name, REFERENCED_TABLE_NAME AS referenced_table, `+
+				`REFERENCED_COLUMN_NAME AS referenced_column FROM information_schema.KEY_COLUMN_USAGE `+
+				`WHERE TABLE_SCHEMA=DATABASE() AND TABLE_NAME=? AND REFERENCED_TABLE_NAME IS NOT NULL `+
+				`ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION`,
+			tableName,
+		)
+
+	case "pgsql":
+		result, err = db.GetAll(ctx,
+			`SELECT c.conname AS name, a.attname AS column_name, rt.relname AS referenced_table, `+
+				`ra.attname AS referenced_column FROM pg_constraint c `+
+				`JOIN pg_class t ON t.oid=c.conrelid JOIN pg_class rt ON rt.oid=c.confrelid `+
+				`JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(col, refcol, seq) ON true `+
+				`JOIN pg_attribute a ON a.attrelid=c.conrelid AND a.attnum=k.col `+
+				`JOIN pg_attribute ra ON ra.attrelid=c.confrelid AND ra.attnum=k.refcol `+
+				`WHERE c.contype='f' AND t.relname=? ORDER BY c.conname, k.seq`,
+			tableName,
+		)
+
+	case "mssql":
+		result, err = db.GetAll(ctx,
+			`SELECT fk.name AS name, c.name AS column_name, rt.name AS referenced_table, `+
+				`rc.name AS referenced_column FROM sys.foreign_keys fk `+
+				`JOIN sys.foreign_key_columns fkc ON fk.object_id=fkc.constraint_object_id `+
+				`JOIN sys.columns c ON fkc.parent_object_id=c.object_id AND fkc.parent_column_id=c.column_id `+
+				`JOIN sys.tables rt ON fkc.referenced_object_id=rt.object_id `+
+				`JOIN sys.columns rc ON fkc.referenced_object_id=rc.object_id AND fkc.referenced_column_id=rc.column_id `+
+				`WHERE fk.parent_object_id=OBJECT_ID(?) ORDER BY fk.name, fkc.constraint_column_id`,
+			tableName,
+		)
+
+	case "sqlite":
+		var list gdb.Result
+		list, err = db.GetAll(ctx, fmt.Sprintf(`PRAGMA foreign_key_list(%s)`, db.GetCore().QuoteWord(tableName)))
+		for _, item := range list {
+			result = append(result, gdb.Record{
+				"name":              gvar.New(fmt.Sprintf(`fk_%s_%s`, tableName, item["id"].String())),
+				"column_name":       item["from"],
+				"referenced_table":  item["table"],
+				"referenced_column": item["to"],
+			})
+		}
+
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var (
+		foreignKeys   = make([]*schemaForeignKey, 0)
+		foreignKeyMap = make(map[string]*schemaForeignKey)
+	)
+	for _, item := range result {
+		name := item["name"].String()
+		foreignKey, ok := foreignKeyMap[name]
+		if !ok {
+			foreignKey = &schemaForeignKey{
+				Name:            name,
+				ReferencedTable: item["referenced_table"].String(),
+			}
+			foreignKeyMap[name] = foreignKey
+			foreignKeys = append(foreignKeys, foreignKey)
+		}
+		foreignKey.Columns = append(foreignKey.Columns, item["column_name"].String())
+		foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, item["referenced_column"].String())
+	}
+	return foreignKeys, nil
+}
+
+// sortTableNamesByDependency sorts the table names that the referenced tables are in front of the referencing ones.
+// The `dependencies` maps table name to the table names it references.
+// The tables in circular dependencies keep their original order.
+func sortTableNamesByDependency(tableNames []string, dependencies map[string][]string) []string {
+	var (
+		sorted   = make([]string, 0, len(tableNames))
+		visited  = make(map[string]bool)
+		visiting = make(map[string]bool)
+		inTables = make(map[string]bool)
+		visit    func(name string)
+	)
+	for _, name := range tableNames {
+		inTables[name] = true
+	}
+	visit = func(name string) {
+		if visited[name] || visiting[name] {
+			return
+		}
+		visiting[name] = true
+		for _, dependency := range dependencies[name] {
+			if inTables[dependency] && dependency != name {
+				visit(dependency)
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+		sorted = append(sorted, name)
+	}
+	for _, name := range tableNames {
+		visit(name)
+	}
+	return sorted
+}
+
+// loadSchemaSnapshot loads and returns the table definitions from snapshot file,
+// which can be any format that gjson supports, like: json/yaml/toml.
+func loadSchemaSnapshot(path string) ([]*schemaTable, error) {
+	if !gfile.Exists(path) {
+		return nil, gerror.Newf(`schema snapshot file "%s" does not exist`, path)
+	}
+	j, err := gjson.Load(path)
+	if err != nil {
+		return nil, gerror.Wrapf(err, `loading schema snapshot file "%s" failed`, path)
+	}
+	var tables []*schemaTable
+	if err = j.Scan(&tables); err != nil {
+		return nil, gerror.Wrapf(err, `invalid schema snapshot file "%s"`, path)
+	}
+	return tables, nil
+}
+
+// saveSchemaSnapshot saves the table definitions to snapshot file in json format.
+func saveSchemaSnapshot(path string, tables []*schemaTable) error {
+	content, err := json.MarshalIndent(tables, "", "\t")
+	if err != nil {
+		return err
+	}
+	return gfile.PutBytes(path, content)
+}
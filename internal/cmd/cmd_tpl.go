@@ -48,6 +48,7 @@ type (
 		Output     string `name:"output"     short:"o" brief:"output file/folder path"`
 		Delimiters string `name:"delimiters" short:"d" brief:"delimiters for template content parsing, default is:{{,}}" d:"{{,}}"`
 		Replace    bool   `name:"replace"    short:"r" brief:"replace original files" orphan:"true"`
+		Force      bool   `name:"force"      short:"f" brief:"overwrite the output files even if they were modified after last parsing" orphan:"true"`
 	}
 	cTplParseOutput struct{}
 )
@@ -78,7 +79,18 @@ func (c *cTpl) Parse(ctx context.Context, in cTplParseInput) (out *cTplParseOutp
 	if len(valuesMap) == 0 {
 		return nil, gerror.Newf(`empty values loaded from values file/folder "%s"`, in.Values)
 	}
+	// The output files are recorded in the manifest, the replaced original files are not recorded.
+	if in.Output != "" {
+		beginGeneratedManifest(getGeneratedManifestScope("tpl parse", in.Output, in.Path))
+	}
 	err = c.parsePath(ctx, valuesMap, in)
+	if in.Output != "" {
+		// Nothing is written if the parsing fails.
+		if err != nil {
+			discardGeneratedFiles()
+		}
+		endGeneratedManifest(err == nil)
+	}
 	if err == nil {
 		mlog.Print("done!")
 	}
@@ -130,7 +142,8 @@ func (c *cTpl) parseFile(ctx context.Context, file string, output string, values
 	}
 	if output != "" {
 		mlog.Printf(`parse file "%s" to "%s"`, file, output)
-		return gfile.PutContents(output, content)
+		// The output is written as it is, which is not formatted even if it is go file.
+		return putGeneratedRawContents(output, content, in.Force)
 	}
 	if in.Replace {
 		mlog.Printf(`parse and replace file "%s"`, file)
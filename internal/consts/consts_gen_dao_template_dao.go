@@ -67,10 +67,7 @@ func New{TplTableNameCamelCase}Dao() *{TplTableNameCamelCase}Dao {
 	}
 }
 
-// DB retrieves and returns the underlying raw database management object of current DAO.
-func (dao *{TplTableNameCamelCase}Dao) DB() gdb.DB {
-	return g.DB(dao.group)
-}
+{TplDbMethod}
 
 // Table returns the table name of current dao.
 func (dao *{TplTableNameCamelCase}Dao) Table() string {
@@ -82,14 +79,15 @@ func (dao *{TplTableNameCamelCase}Dao) Columns() {TplTableNameCamelCase}Columns
 	return dao.columns
 }
 
-// Group returns the configuration group name of database of current dao.
-func (dao *{TplTableNameCamelCase}Dao) Group() string {
-	return dao.group
-}
+{TplGroupMethod}
+
+{TplCtxMethods}
+`
 
+const TemplateDaoDaoInternalCtxContent = `
 // Ctx creates and returns the Model for current DAO, It automatically sets the context for current operation.
 func (dao *{TplTableNameCamelCase}Dao) Ctx(ctx context.Context) *gdb.Model {
-	return dao.DB().Model(dao.table).Safe().Ctx(ctx)
+	return {TplDbCall}.Model(dao.table).Safe().Ctx(ctx)
 }
 
 // Transaction wraps the transaction logic using function f.
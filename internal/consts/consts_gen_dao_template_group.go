@@ -0,0 +1,98 @@
+package consts
+
+const TemplateDaoDaoInternalDbContent = `
+// DB retrieves and returns the underlying raw database management object of current DAO.
+func (dao *{TplTableNameCamelCase}Dao) DB() gdb.DB {
+	return g.DB(dao.group)
+}
+`
+
+const TemplateDaoDaoInternalGroupContent = `
+// Group returns the configuration group name of database of current dao.
+func (dao *{TplTableNameCamelCase}Dao) Group() string {
+	return dao.group
+}
+`
+
+const TemplateDaoDaoInternalDbResolverContent = `
+// DB retrieves and returns the underlying raw database management object of current DAO.
+// The database group is resolved from the optional context ctx by the group resolver, see SetGroupResolver.
+func (dao *{TplTableNameCamelCase}Dao) DB(ctx ...context.Context) gdb.DB {
+	return g.DB(dao.Group(ctx...))
+}
+`
+
+const TemplateDaoDaoInternalGroupResolverContent = `
+// Group returns the configuration group name of database of current dao.
+// The group is resolved from the optional context ctx by the group resolver, see SetGroupResolver.
+func (dao *{TplTableNameCamelCase}Dao) Group(ctx ...context.Context) string {
+	if len(ctx) > 0 && ctx[0] != nil {
+		return ResolveGroup(ctx[0], dao.group)
+	}
+	return dao.group
+}
+`
+
+const TemplateDaoGroupResolverInternalContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT. Created at {TplDatetime}
+// ==========================================================================
+
+package internal
+
+import (
+	"context"
+)
+
+// GroupResolver resolves the database configuration group name of DAO at call time from the context,
+// in which the group is the configured group of the DAO.
+// It is usually used for multi-tenant database routing, like resolving the tenant group from the context.
+type GroupResolver func(ctx context.Context, group string) string
+
+// groupResolver is the group resolver of all DAOs, which is nil in default.
+var groupResolver GroupResolver
+
+// SetGroupResolver sets the group resolver of all DAOs.
+// Note that it is not concurrent-safe, it should be called in booting before any DAO operation.
+func SetGroupResolver(resolver GroupResolver) {
+	groupResolver = resolver
+}
+
+// ResolveGroup resolves and returns the database configuration group name from ctx using the group resolver.
+// It falls back to the configured group if the group resolver is not set or returns empty string.
+func ResolveGroup(ctx context.Context, group string) string {
+	if groupResolver != nil {
+		if resolved := groupResolver(ctx, group); resolved != "" {
+			return resolved
+		}
+	}
+	return group
+}
+`
+
+const TemplateDaoGroupResolverIndexContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT. Created at {TplDatetime}
+// ==========================================================================
+
+package dao
+
+import (
+	"{TplImportPrefix}/internal"
+)
+
+// GroupResolver resolves the database configuration group name of DAO at call time from the context,
+// in which the group is the configured group of the DAO.
+type GroupResolver = internal.GroupResolver
+
+// SetGroupResolver sets the group resolver of all DAOs, which is used by their DB/Ctx/Transaction methods.
+// The configured group of DAO is used if the group resolver returns empty string.
+// Note that it is not concurrent-safe, it should be called in booting before any DAO operation, eg:
+//
+//	dao.SetGroupResolver(func(ctx context.Context, group string) string {
+//		return "tenant_" + g.RequestFromCtx(ctx).GetHeader("X-Tenant-Id")
+//	})
+func SetGroupResolver(resolver GroupResolver) {
+	internal.SetGroupResolver(resolver)
+}
+`
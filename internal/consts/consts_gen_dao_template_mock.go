@@ -0,0 +1,225 @@
+package consts
+
+const TemplateDaoDaoInternalMethodsContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT. Created at {TplDatetime}
+// ==========================================================================
+
+package internal
+
+import (
+	"context"
+
+	"{TplEntityImport}"
+)
+
+// Get retrieves and returns the record of table {TplTableName} by primary key.
+// It returns nil if the record does not exist.
+func (dao *{TplTableNameCamelCase}Dao) Get(ctx context.Context, key {TplPrimaryKeyType}) (*entity.{TplTableNameCamelCase}, error) {
+	one, err := dao.Ctx(ctx).WherePri(key).One()
+	if err != nil || one.IsEmpty() {
+		return nil, err
+	}
+	var record *entity.{TplTableNameCamelCase}
+	if err = one.Struct(&record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// All retrieves and returns all records of table {TplTableName} ordered by primary key.
+func (dao *{TplTableNameCamelCase}Dao) All(ctx context.Context) ([]*entity.{TplTableNameCamelCase}, error) {
+	result, err := dao.Ctx(ctx).OrderAsc(dao.columns.{TplPrimaryKey}).All()
+	if err != nil {
+		return nil, err
+	}
+	var list []*entity.{TplTableNameCamelCase}
+	if err = result.Structs(&list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Insert inserts the record into table {TplTableName}.
+func (dao *{TplTableNameCamelCase}Dao) Insert(ctx context.Context, data *entity.{TplTableNameCamelCase}) error {
+	{TplInsertContent}
+}
+
+// Update updates the record of table {TplTableName} by its primary key.
+func (dao *{TplTableNameCamelCase}Dao) Update(ctx context.Context, data *entity.{TplTableNameCamelCase}) error {
+	_, err := dao.Ctx(ctx).Data(data).WherePri(data.{TplPrimaryKey}).Update()
+	return err
+}
+
+// Delete deletes the record of table {TplTableName} by primary key.
+func (dao *{TplTableNameCamelCase}Dao) Delete(ctx context.Context, key {TplPrimaryKeyType}) error {
+	_, err := dao.Ctx(ctx).WherePri(key).Delete()
+	return err
+}
+`
+
+const TemplateDaoDaoInterfaceContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT. Created at {TplDatetime}
+// ==========================================================================
+
+package dao
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"{TplImportPrefix}/internal"
+	"{TplEntityImport}"
+	{TplPackageImports}
+)
+
+// I{TplTableNameCamelCase} is the interface of data access object for table {TplTableName}.
+// It is implemented by the DAO {TplTableNameCamelCase} and the in-memory fake in package "fake",
+// so that your logic can depend on it and be tested without database.
+type I{TplTableNameCamelCase} interface {
+	DB({TplGroupMethodArgs}) gdb.DB
+	Table() string
+	Columns() internal.{TplTableNameCamelCase}Columns
+	Group({TplGroupMethodArgs}) string
+	Ctx(ctx context.Context) *gdb.Model
+	Transaction(ctx context.Context, f func(ctx context.Context, tx *gdb.TX) error) (err error)
+	Get(ctx context.Context, key {TplPrimaryKeyType}) (*entity.{TplTableNameCamelCase}, error)
+	All(ctx context.Context) ([]*entity.{TplTableNameCamelCase}, error)
+	Insert(ctx context.Context, data *entity.{TplTableNameCamelCase}) error
+	Update(ctx context.Context, data *entity.{TplTableNameCamelCase}) error
+	Delete(ctx context.Context, key {TplPrimaryKeyType}) error
+	{TplCustomMethods}
+}
+
+var _ I{TplTableNameCamelCase} = {TplTableNameCamelCase}
+`
+
+const TemplateDaoDaoFakeContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT. Created at {TplDatetime}
+// ==========================================================================
+
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"{TplImportPrefix}"
+	"{TplImportPrefix}/internal"
+	"{TplEntityImport}"
+)
+
+// {TplTableNameCamelCase}Dao is the in-memory fake of data access object for table {TplTableName},
+// in which the records are keyed by primary key.
+// The methods that cannot be faked, like DB, Ctx, Transaction and your custom methods,
+// are delegated to the embedded dao.I{TplTableNameCamelCase}, which can be set as you wish in testing.
+type {TplTableNameCamelCase}Dao struct {
+	dao.I{TplTableNameCamelCase}
+	mu      sync.RWMutex
+	keys    []{TplPrimaryKeyType}
+	records map[{TplPrimaryKeyType}]*entity.{TplTableNameCamelCase}
+	{TplFakeFields}
+}
+
+var _ dao.I{TplTableNameCamelCase} = (*{TplTableNameCamelCase}Dao)(nil)
+
+// New{TplTableNameCamelCase}Dao creates and returns a fake DAO with given records for table {TplTableName}.
+func New{TplTableNameCamelCase}Dao(records ...*entity.{TplTableNameCamelCase}) *{TplTableNameCamelCase}Dao {
+	f := &{TplTableNameCamelCase}Dao{
+		records: make(map[{TplPrimaryKeyType}]*entity.{TplTableNameCamelCase}),
+	}
+	for _, record := range records {
+		if err := f.Insert(context.Background(), record); err != nil {
+			panic(err)
+		}
+	}
+	return f
+}
+
+// Table returns the table name of current dao.
+func (f *{TplTableNameCamelCase}Dao) Table() string {
+	return "{TplTableName}"
+}
+
+// Columns returns all column names of current dao.
+func (f *{TplTableNameCamelCase}Dao) Columns() internal.{TplTableNameCamelCase}Columns {
+	return internal.New{TplTableNameCamelCase}Dao().Columns()
+}
+
+// Group returns the configuration group name of database of current dao.
+func (f *{TplTableNameCamelCase}Dao) Group({TplGroupMethodArgs}) string {
+	return "{TplGroupName}"
+}
+
+// Get retrieves and returns a copy of the record by primary key.
+// It returns nil if the record does not exist.
+func (f *{TplTableNameCamelCase}Dao) Get(ctx context.Context, key {TplPrimaryKeyType}) (*entity.{TplTableNameCamelCase}, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if record, ok := f.records[key]; ok {
+		clone := *record
+		return &clone, nil
+	}
+	return nil, nil
+}
+
+// All retrieves and returns copies of all records in inserting order.
+func (f *{TplTableNameCamelCase}Dao) All(ctx context.Context) ([]*entity.{TplTableNameCamelCase}, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	list := make([]*entity.{TplTableNameCamelCase}, 0, len(f.keys))
+	for _, key := range f.keys {
+		clone := *f.records[key]
+		list = append(list, &clone)
+	}
+	return list, nil
+}
+
+// Insert stores a copy of the record, it returns error if the primary key already exists.
+func (f *{TplTableNameCamelCase}Dao) Insert(ctx context.Context, data *entity.{TplTableNameCamelCase}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.records == nil {
+		f.records = make(map[{TplPrimaryKeyType}]*entity.{TplTableNameCamelCase})
+	}
+	{TplFakeInsertContent}
+	if _, ok := f.records[data.{TplPrimaryKey}]; ok {
+		return gerror.Newf(` + "`" + `duplicate entry "%v" for primary key of table "{TplTableName}"` + "`" + `, data.{TplPrimaryKey})
+	}
+	clone := *data
+	f.keys = append(f.keys, data.{TplPrimaryKey})
+	f.records[data.{TplPrimaryKey}] = &clone
+	return nil
+}
+
+// Update replaces the stored record by its primary key, it does nothing if the record does not exist.
+func (f *{TplTableNameCamelCase}Dao) Update(ctx context.Context, data *entity.{TplTableNameCamelCase}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.records[data.{TplPrimaryKey}]; ok {
+		clone := *data
+		f.records[data.{TplPrimaryKey}] = &clone
+	}
+	return nil
+}
+
+// Delete deletes the record by primary key, it does nothing if the record does not exist.
+func (f *{TplTableNameCamelCase}Dao) Delete(ctx context.Context, key {TplPrimaryKeyType}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.records[key]; !ok {
+		return nil
+	}
+	delete(f.records, key)
+	for i, k := range f.keys {
+		if k == key {
+			f.keys = append(f.keys[:i], f.keys[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+`
@@ -0,0 +1,62 @@
+package consts
+
+const TemplateDaoRoutineIndexContent = `
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package dao
+
+import (
+	"{TplImportPrefix}/internal"
+)
+
+// {TplRoutineNameCamelLowerCase}Dao is the data access object for stored procedures and functions of database group {TplGroupName}.
+// You can define custom methods on it to extend its functionality as you wish.
+type {TplRoutineNameCamelLowerCase}Dao struct {
+	*internal.{TplRoutineNameCamelCase}Dao
+}
+
+var (
+	// {TplRoutineNameCamelCase} is globally public accessible object for stored procedures and functions calling.
+	{TplRoutineNameCamelCase} = {TplRoutineNameCamelLowerCase}Dao{
+		internal.New{TplRoutineNameCamelCase}Dao(),
+	}
+)
+
+// Fill with you ideas below.
+
+`
+
+const TemplateDaoRoutineInternalContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT. Created at {TplDatetime}
+// ==========================================================================
+
+package internal
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// {TplRoutineNameCamelCase}Dao is the data access object for stored procedures and functions of database group {TplGroupName}.
+type {TplRoutineNameCamelCase}Dao struct {
+	group string // group is the database configuration group name of current DAO.
+}
+
+// New{TplRoutineNameCamelCase}Dao creates and returns a new DAO object for stored procedures and functions calling.
+func New{TplRoutineNameCamelCase}Dao() *{TplRoutineNameCamelCase}Dao {
+	return &{TplRoutineNameCamelCase}Dao{
+		group: "{TplGroupName}",
+	}
+}
+
+{TplDbMethod}
+
+{TplGroupMethod}
+
+{TplRoutineDefine}
+`
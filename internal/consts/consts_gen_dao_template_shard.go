@@ -0,0 +1,53 @@
+package consts
+
+const TemplateDaoDaoInternalShardContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT. Created at {TplDatetime}
+// ==========================================================================
+
+package internal
+
+import (
+	{TplPackageImports}
+)
+
+// {TplTableNameCamelLowerCase}ShardTables holds all the physical table names of sharded table {TplTableName}.
+var {TplTableNameCamelLowerCase}ShardTables = []string{
+	{TplShardTables}
+}
+
+// ShardTables returns all the physical table names of sharded table {TplTableName}.
+func (dao *{TplTableNameCamelCase}Dao) ShardTables() []string {
+	return {TplTableNameCamelLowerCase}ShardTables
+}
+
+// ShardTable resolves and returns the physical table name of sharded table {TplTableName} by shard key.
+// {TplShardComment}
+func (dao *{TplTableNameCamelCase}Dao) ShardTable(key interface{}) string {
+	{TplShardResolver}
+}
+
+// Shard creates and returns the Model on the physical table resolved by shard key,
+// It automatically sets the context for current operation.
+func (dao *{TplTableNameCamelCase}Dao) Shard(ctx context.Context, key interface{}) *gdb.Model {
+	return {TplDbCall}.Model(dao.ShardTable(key)).Safe().Ctx(ctx)
+}
+`
+
+const TemplateDaoDaoInternalShardCtxContent = `
+// Ctx is not supported by sharded table {TplTableName}, as there is no physical table of the logical name.
+// It panics to fail clearly, use Shard(ctx, key) creating the Model on the physical table resolved by shard key instead.
+func (dao *{TplTableNameCamelCase}Dao) Ctx(ctx context.Context) *gdb.Model {
+	panic("sharded table \"{TplTableName}\" has no physical table of the name, use \"Shard(ctx, key)\" instead of \"Ctx(ctx)\"")
+}
+
+// Transaction wraps the transaction logic using function f.
+// It rollbacks the transaction and returns the error from function f if it returns non-nil error.
+// It commits the transaction and returns nil if function f returns nil.
+//
+// Note that, you should not Commit or Rollback the transaction in function f
+// as it is automatically handled by this function.
+func (dao *{TplTableNameCamelCase}Dao) Transaction(ctx context.Context, f func(ctx context.Context, tx *gdb.TX) error) (err error) {
+	return {TplDbCall}.Transaction(ctx, f)
+}
+`
@@ -0,0 +1,111 @@
+package consts
+
+const TemplateDaoTypedColumnContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT. Created at {TplDatetime}
+// ==========================================================================
+
+package internal
+
+import (
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+// TypedColumn describes a table column with its golang type, nullability and database type.
+type TypedColumn struct {
+	Name     string // Name is the column name in table.
+	GoType   string // GoType is the golang type of the column in generated entity.
+	DbType   string // DbType is the column type in database.
+	Nullable bool   // Nullable specifies whether the column can be NULL.
+}
+
+// String returns the column name, which makes the descriptor usable as column name.
+func (c TypedColumn) String() string {
+	return c.Name
+}
+
+// IsNull returns the model handler that adds "column IS NULL" condition.
+func (c TypedColumn) IsNull() gdb.ModelHandler {
+	return func(m *gdb.Model) *gdb.Model {
+		return m.WhereNull(c.Name)
+	}
+}
+
+// IsNotNull returns the model handler that adds "column IS NOT NULL" condition.
+func (c TypedColumn) IsNotNull() gdb.ModelHandler {
+	return func(m *gdb.Model) *gdb.Model {
+		return m.WhereNotNull(c.Name)
+	}
+}
+
+{TplTypedColumnDefine}
+`
+
+const TemplateDaoTypedColumnTypeContent = `
+// {TplTypedColumnName} is the typed column descriptor for golang type {TplTypedColumnGoType}.
+type {TplTypedColumnName} struct {
+	TypedColumn
+}
+`
+
+const TemplateDaoTypedColumnEqContent = `
+// Eq returns the model handler that adds "column = value" condition.
+func (c {TplTypedColumnName}) Eq(value {TplTypedColumnGoType}) gdb.ModelHandler {
+	return func(m *gdb.Model) *gdb.Model {
+		return m.Where(c.Name, value)
+	}
+}
+
+// In returns the model handler that adds "column IN (values)" condition.
+func (c {TplTypedColumnName}) In(values ...{TplTypedColumnGoType}) gdb.ModelHandler {
+	return func(m *gdb.Model) *gdb.Model {
+		return m.WhereIn(c.Name, values)
+	}
+}
+`
+
+const TemplateDaoTypedColumnBetweenContent = `
+// Between returns the model handler that adds "column BETWEEN min AND max" condition.
+func (c {TplTypedColumnName}) Between(min, max {TplTypedColumnGoType}) gdb.ModelHandler {
+	return func(m *gdb.Model) *gdb.Model {
+		return m.WhereBetween(c.Name, min, max)
+	}
+}
+`
+
+const TemplateDaoTypedColumnLikeContent = `
+// Like returns the model handler that adds "column LIKE pattern" condition.
+func (c {TplTypedColumnName}) Like(pattern string) gdb.ModelHandler {
+	return func(m *gdb.Model) *gdb.Model {
+		return m.WhereLike(c.Name, pattern)
+	}
+}
+`
+
+const TemplateDaoDaoInternalTypedContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT. Created at {TplDatetime}
+// ==========================================================================
+
+package internal
+
+// {TplTableNameCamelCase}TypedColumns defines and stores typed column descriptors for table {TplTableName}.
+type {TplTableNameCamelCase}TypedColumns struct {
+	{TplColumnDefine}
+}
+
+// {TplTableNameCamelLowerCase}TypedColumns holds the typed column descriptors for table {TplTableName}.
+var {TplTableNameCamelLowerCase}TypedColumns = {TplTableNameCamelCase}TypedColumns{
+	{TplColumnNames}
+}
+
+// TypedColumns returns all typed column descriptors of current dao,
+// which can be used for building type-checked conditions, like:
+// dao.Ctx(ctx).Handler(dao.TypedColumns().Id.Eq(1)).
+func (dao *{TplTableNameCamelCase}Dao) TypedColumns() {TplTableNameCamelCase}TypedColumns {
+	return {TplTableNameCamelLowerCase}TypedColumns
+}
+`
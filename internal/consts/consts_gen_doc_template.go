@@ -0,0 +1,68 @@
+package consts
+
+const TemplateGenDocHtmlContent = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 14px; color: #24292f; margin: 0; display: flex; }
+nav { width: 240px; min-width: 240px; height: 100vh; overflow-y: auto; position: sticky; top: 0; background: #f6f8fa; border-right: 1px solid #d0d7de; padding: 16px; box-sizing: border-box; }
+nav a { display: block; color: #0969da; text-decoration: none; padding: 2px 0; word-break: break-all; }
+main { flex: 1; padding: 16px 32px; overflow-x: auto; }
+h1 { font-size: 24px; }
+h2 { font-size: 20px; border-bottom: 1px solid #d0d7de; padding-bottom: 4px; margin-top: 40px; }
+h3 { font-size: 16px; }
+table { border-collapse: collapse; margin: 8px 0 16px 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code { font-family: SFMono-Regular, Consolas, Menlo, monospace; font-size: 12px; }
+.comment { color: #57606a; }
+</style>
+</head>
+<body>
+<nav>
+<strong>Tables</strong>
+{{- range .Tables}}
+<a href="#{{.Name}}">{{.Name}}</a>
+{{- end}}
+</nav>
+<main>
+<h1>{{.Title}}</h1>
+<p class="comment">Generated by "gf gen doc" at {{.CreatedAt}}</p>
+{{- range .Tables}}
+<h2 id="{{.Name}}">{{.Name}}</h2>
+{{- if .Comment}}
+<p>{{.Comment}}</p>
+{{- end}}
+<p>Entity: <code>entity.{{.Entity}}</code></p>
+<table>
+<tr><th>#</th><th>Column</th><th>Type</th><th>Null</th><th>Default</th><th>Key</th><th>Extra</th><th>Comment</th><th>Field</th><th>Go Type</th><th>JSON</th></tr>
+{{- range .Columns}}
+<tr><td>{{.Index}}</td><td><code>{{.Name}}</code></td><td><code>{{.Type}}</code></td><td>{{.Null}}</td><td>{{.Default}}</td><td>{{.Key}}</td><td>{{.Extra}}</td><td>{{.Comment}}</td><td><code>{{.Field}}</code></td><td><code>{{.GoType}}</code></td><td><code>{{.Json}}</code></td></tr>
+{{- end}}
+</table>
+{{- if .Indexes}}
+<h3>Indexes</h3>
+<table>
+<tr><th>Name</th><th>Columns</th><th>Unique</th><th>Primary</th></tr>
+{{- range .Indexes}}
+<tr><td><code>{{.Name}}</code></td><td><code>{{.Columns}}</code></td><td>{{.Unique}}</td><td>{{.Primary}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .ForeignKeys}}
+<h3>Foreign Keys</h3>
+<table>
+<tr><th>Name</th><th>Columns</th><th>References</th></tr>
+{{- range .ForeignKeys}}
+<tr><td><code>{{.Name}}</code></td><td><code>{{.Columns}}</code></td><td><a href="#{{.ReferencedTable}}">{{.ReferencedTable}}</a> (<code>{{.ReferencedColumns}}</code>)</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</main>
+</body>
+</html>
+`
@@ -9,7 +9,7 @@ syntax = "proto3";
 
 package {PackageName};
 
-import "github.com/gogo/protobuf/gogoproto/gogo.proto";
+{ImportContent}
 
 {OptionContent}
 
@@ -0,0 +1,47 @@
+package consts
+
+const TemplatePbEntityConverterContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT.
+// ==========================================================================
+
+package {TplPackageName}
+
+import (
+	{TplPackageImports}
+)
+
+// {TplEntityName}ToPb converts entity {TplEntityName} to protobuf message {TplMessageName}.
+func {TplEntityName}ToPb(in *entity.{TplEntityName}) *pbentity.{TplMessageName} {
+	if in == nil {
+		return nil
+	}
+	return &pbentity.{TplMessageName}{
+		{TplToPbFields}
+	}
+}
+
+// {TplEntityName}FromPb converts protobuf message {TplMessageName} to entity {TplEntityName}.
+func {TplEntityName}FromPb(in *pbentity.{TplMessageName}) *entity.{TplEntityName} {
+	if in == nil {
+		return nil
+	}
+	return &entity.{TplEntityName}{
+		{TplFromPbFields}
+	}
+}
+`
+
+const TemplatePbEntityConverterHelperContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT.
+// ==========================================================================
+
+package {TplPackageName}
+
+import (
+	{TplPackageImports}
+)
+
+{TplHelpers}
+`
@@ -0,0 +1,22 @@
+package consts
+
+const TemplatePbEntityServiceContent = `
+// ==========================================================================
+// Code generated by GoFrame CLI tool. DO NOT EDIT.
+// ==========================================================================
+
+syntax = "proto3";
+
+package {PackageName};
+
+{ImportContent}
+
+{OptionContent}
+
+// {ServiceName} provides the CRUD operations of table {TableName}.
+service {ServiceName} {
+{RpcContent}
+}
+
+{MessageContent}
+`
@@ -1,18 +1,303 @@
 package utils
 
 import (
-	"fmt"
-	"github.com/gogf/gf/v2/os/gproc"
-)
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
 
-var (
-	// gofmtPath is the binary path of command `gofmt`.
-	gofmtPath = gproc.SearchBinaryPath("gofmt")
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gfile"
 )
 
-// GoFmt formats the source file using command `gofmt -w -s PATH`.
-func GoFmt(path string) {
-	if gofmtPath != "" {
-		gproc.ShellExec(fmt.Sprintf(`%s -w -s %s`, gofmtPath, path))
+// knownImports is the mapping from package name to import path,
+// which is used for adding missing imports of generated go files.
+var knownImports = map[string]string{
+	"context": "context",
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"strings": "strings",
+	"time":    "time",
+	"g":       "github.com/gogf/gf/v2/frame/g",
+	"gdb":     "github.com/gogf/gf/v2/database/gdb",
+	"gjson":   "github.com/gogf/gf/v2/encoding/gjson",
+	"gerror":  "github.com/gogf/gf/v2/errors/gerror",
+	"gcode":   "github.com/gogf/gf/v2/errors/gcode",
+	"gtime":   "github.com/gogf/gf/v2/os/gtime",
+	"gstr":    "github.com/gogf/gf/v2/text/gstr",
+	"gconv":   "github.com/gogf/gf/v2/util/gconv",
+	"gmeta":   "github.com/gogf/gf/v2/util/gmeta",
+	"gvar":    "github.com/gogf/gf/v2/container/gvar",
+}
+
+// GoFmt formats the go source file in-process like `gofmt -s`,
+// and fixes its imports like `goimports`, which removes the unused imports and adds the missing known imports.
+// It returns error with the file path if the file cannot be parsed.
+func GoFmt(path string) error {
+	src := gfile.GetBytes(path)
+	result, err := GoFmtSource(src)
+	if err != nil {
+		return gerror.Wrapf(err, `format go file "%s" failed`, path)
+	}
+	if bytes.Equal(src, result) {
+		return nil
+	}
+	if err = gfile.PutBytes(path, result); err != nil {
+		return gerror.Wrapf(err, `write formatted go file "%s" failed`, path)
+	}
+	return nil
+}
+
+// GoFmtSource formats the go source `src` and fixes its imports, see GoFmt.
+func GoFmtSource(src []byte) ([]byte, error) {
+	var (
+		fileSet   = token.NewFileSet()
+		file, err = parser.ParseFile(fileSet, "", src, parser.ParseComments)
+	)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		imports     = make([]*ast.ImportSpec, 0)
+		importDecls = make([]*ast.GenDecl, 0)
+		usedNames   = getUsedPackageNames(file)
+		importNames = make(map[string]bool)
+	)
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			importDecls = append(importDecls, genDecl)
+		}
+	}
+	// Unused imports pruning, the imports whose package names are not certain are kept.
+	for _, spec := range file.Imports {
+		name, certain := getImportName(spec)
+		switch {
+		case name == "_" || name == "." || !certain:
+		case !usedNames[name] || importNames[name]:
+			continue
+		}
+		importNames[name] = true
+		imports = append(imports, spec)
+	}
+	// Missing known imports adding.
+	for name := range usedNames {
+		if path, ok := knownImports[name]; ok && !importNames[name] {
+			imports = append(imports, &ast.ImportSpec{
+				Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)},
+			})
+		}
+	}
+	if len(imports) != len(file.Imports) || hasMissingImport(imports, file.Imports) {
+		src = replaceImportDecls(fileSet, src, file, importDecls, imports)
+		fileSet = token.NewFileSet()
+		if file, err = parser.ParseFile(fileSet, "", src, parser.ParseComments); err != nil {
+			return nil, err
+		}
+	}
+	ast.Walk(simplifier{}, file)
+	var buffer bytes.Buffer
+	if err = format.Node(&buffer, fileSet, file); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// getUsedPackageNames returns the names of identifiers used as package qualifiers in `file`,
+// which are the unresolved identifiers of selector expressions.
+func getUsedPackageNames(file *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok && ident.Obj == nil {
+				names[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// getImportName returns the package name of import `spec` and whether the name is certain.
+// The name is certain if it is explicit or the import is known, or else it is guessed
+// as the last element of import path without version suffix.
+func getImportName(spec *ast.ImportSpec) (name string, certain bool) {
+	if spec.Name != nil {
+		return spec.Name.Name, true
+	}
+	path, _ := strconv.Unquote(spec.Path.Value)
+	for knownName, knownPath := range knownImports {
+		if knownPath == path {
+			return knownName, true
+		}
+	}
+	name = path[strings.LastIndex(path, "/")+1:]
+	if pos := strings.Index(name, ".v"); pos > 0 {
+		name = name[:pos]
+	}
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		// Major version suffix like ".../v2".
+		trimmed := strings.TrimSuffix(path, "/"+name)
+		name = trimmed[strings.LastIndex(trimmed, "/")+1:]
 	}
+	return strings.TrimPrefix(name, "go-"), false
+}
+
+// hasMissingImport checks whether any of `imports` is not in `origin`.
+func hasMissingImport(imports, origin []*ast.ImportSpec) bool {
+	for _, spec := range imports {
+		found := false
+		for _, v := range origin {
+			if v == spec {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceImportDecls replaces the import declarations `importDecls` of `src` with a single import declaration
+// of `imports`, in which the standard packages and the others are grouped separately.
+func replaceImportDecls(
+	fileSet *token.FileSet, src []byte, file *ast.File, importDecls []*ast.GenDecl, imports []*ast.ImportSpec,
+) []byte {
+	var (
+		stdLines   = make([]string, 0)
+		otherLines = make([]string, 0)
+	)
+	for _, spec := range imports {
+		line := spec.Path.Value
+		if spec.Name != nil {
+			line = spec.Name.Name + " " + line
+		}
+		if path, _ := strconv.Unquote(spec.Path.Value); strings.Contains(strings.Split(path, "/")[0], ".") {
+			otherLines = append(otherLines, line)
+		} else {
+			stdLines = append(stdLines, line)
+		}
+	}
+	sort.Strings(stdLines)
+	sort.Strings(otherLines)
+	var block bytes.Buffer
+	if len(imports) > 0 {
+		block.WriteString("\n\nimport (\n")
+		block.WriteString(strings.Join(stdLines, "\n"))
+		if len(stdLines) > 0 && len(otherLines) > 0 {
+			block.WriteString("\n\n")
+		}
+		block.WriteString(strings.Join(otherLines, "\n"))
+		block.WriteString("\n)\n")
+	}
+	// The import declarations are removed from end to beginning, so that the offsets are not changed.
+	result := append([]byte(nil), src...)
+	for i := len(importDecls) - 1; i >= 0; i-- {
+		var (
+			start = fileSet.Position(importDecls[i].Pos()).Offset
+			end   = fileSet.Position(importDecls[i].End()).Offset
+		)
+		result = append(result[:start], result[end:]...)
+	}
+	var (
+		packageEnd = fileSet.Position(file.Name.End()).Offset
+		buffer     bytes.Buffer
+	)
+	buffer.Write(result[:packageEnd])
+	buffer.Write(block.Bytes())
+	buffer.Write(result[packageEnd:])
+	return buffer.Bytes()
+}
+
+// simplifier simplifies the go source like `gofmt -s`, which:
+// omits the types of composite literal elements that are same as the element type,
+// omits the high bound of slice expression like `s[a:len(s)]`,
+// and omits the blank variables of range statement like `for x, _ = range v`.
+type simplifier struct{}
+
+// Visit implements interface ast.Visitor.
+func (s simplifier) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.CompositeLit:
+		var keyType, eltType ast.Expr
+		switch typ := n.Type.(type) {
+		case *ast.ArrayType:
+			eltType = typ.Elt
+		case *ast.MapType:
+			keyType = typ.Key
+			eltType = typ.Value
+		}
+		if eltType == nil {
+			break
+		}
+		for i, elt := range n.Elts {
+			eltPtr := &n.Elts[i]
+			if keyValue, ok := elt.(*ast.KeyValueExpr); ok {
+				if keyType != nil {
+					s.simplifyLiteral(keyType, keyValue.Key, &keyValue.Key)
+				}
+				elt, eltPtr = keyValue.Value, &keyValue.Value
+			}
+			s.simplifyLiteral(eltType, elt, eltPtr)
+		}
+		// The elements are already walked.
+		ast.Walk(s, n.Type)
+		return nil
+
+	case *ast.SliceExpr:
+		if n.Max != nil {
+			break
+		}
+		if ident, ok := n.X.(*ast.Ident); ok && ident.Obj != nil {
+			if call, ok := n.High.(*ast.CallExpr); ok && len(call.Args) == 1 && !call.Ellipsis.IsValid() {
+				if fun, ok := call.Fun.(*ast.Ident); ok && fun.Name == "len" && fun.Obj == nil {
+					if arg, ok := call.Args[0].(*ast.Ident); ok && arg.Obj == ident.Obj {
+						n.High = nil
+					}
+				}
+			}
+		}
+
+	case *ast.RangeStmt:
+		if isBlankIdent(n.Value) {
+			n.Value = nil
+		}
+		if isBlankIdent(n.Key) && n.Value == nil {
+			n.Key = nil
+		}
+	}
+	return s
+}
+
+// simplifyLiteral omits the type of composite literal `expr` if it is same as `typ`,
+// `exprPtr` is replaced with the composite literal if `expr` is its address and `typ` is the pointer type.
+func (s simplifier) simplifyLiteral(typ ast.Expr, expr ast.Expr, exprPtr *ast.Expr) {
+	ast.Walk(s, expr)
+	if inner, ok := expr.(*ast.CompositeLit); ok && inner.Type != nil {
+		if types.ExprString(inner.Type) == types.ExprString(typ) {
+			inner.Type = nil
+		}
+	}
+	if ptr, ok := typ.(*ast.StarExpr); ok {
+		if addr, ok := expr.(*ast.UnaryExpr); ok && addr.Op == token.AND {
+			if inner, ok := addr.X.(*ast.CompositeLit); ok && inner.Type != nil {
+				if types.ExprString(inner.Type) == types.ExprString(ptr.X) {
+					inner.Type = nil
+					*exprPtr = inner
+				}
+			}
+		}
+	}
+}
+
+// isBlankIdent checks whether `expr` is the blank identifier.
+func isBlankIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
 }
@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/v2/test/gtest"
+)
+
+func Test_GoFmtSource(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var cases = []struct {
+			name   string
+			src    string
+			result string
+		}{
+			{
+				name: "unused imports pruning",
+				src: `package a
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+var s = strings.TrimSpace(" a ")
+`,
+				result: `package a
+
+import (
+	"strings"
+)
+
+var s = strings.TrimSpace(" a ")
+`,
+			},
+			{
+				name: "missing known imports adding",
+				src: `package a
+
+var (
+	t = time.Now()
+	m = g.Map{}
+)
+`,
+				result: `package a
+
+import (
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+var (
+	t = time.Now()
+	m = g.Map{}
+)
+`,
+			},
+			{
+				name: "aliased imports",
+				src: `package a
+
+import (
+	str "strings"
+	gstr "github.com/gogf/gf/v2/text/gstr"
+	unused "github.com/gogf/gf/v2/util/gconv"
+)
+
+var (
+	a = str.TrimSpace(" a ")
+	b = gstr.Trim(" b ")
+)
+`,
+				result: `package a
+
+import (
+	str "strings"
+
+	gstr "github.com/gogf/gf/v2/text/gstr"
+)
+
+var (
+	a = str.TrimSpace(" a ")
+	b = gstr.Trim(" b ")
+)
+`,
+			},
+			{
+				name: "unknown imports keeping",
+				src: `package a
+
+import (
+	"fmt"
+
+	"github.com/foo/bar-go"
+	"gopkg.in/yaml.v3"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+var s = fmt.Sprint(unknown.Value)
+`,
+				result: `package a
+
+import (
+	"fmt"
+
+	"github.com/foo/bar-go"
+	_ "github.com/go-sql-driver/mysql"
+	"gopkg.in/yaml.v3"
+)
+
+var s = fmt.Sprint(unknown.Value)
+`,
+			},
+			{
+				name: "certain unknown imports pruning",
+				src: `package a
+
+import (
+	"fmt"
+
+	v1 "github.com/foo/bar/api/v1"
+)
+
+var s = fmt.Sprint(1)
+`,
+				result: `package a
+
+import (
+	"fmt"
+)
+
+var s = fmt.Sprint(1)
+`,
+			},
+			{
+				name: "simplification",
+				src: `package a
+
+type T struct{ A int }
+
+var (
+	s = []T{T{A: 1}, T{A: 2}}
+	p = []*T{&T{A: 1}}
+	m = map[string]T{"a": T{A: 1}}
+	b = "abc"[1:len("abc")]
+)
+
+func f(v []int) {
+	for i, _ := range v {
+		_ = i
+	}
+	for _ = range v {
+	}
+}
+`,
+				result: `package a
+
+type T struct{ A int }
+
+var (
+	s = []T{{A: 1}, {A: 2}}
+	p = []*T{{A: 1}}
+	m = map[string]T{"a": {A: 1}}
+	b = "abc"[1:len("abc")]
+)
+
+func f(v []int) {
+	for i := range v {
+		_ = i
+	}
+	for range v {
+	}
+}
+`,
+			},
+		}
+		for _, c := range cases {
+			result, err := GoFmtSource([]byte(c.src))
+			t.AssertNil(err)
+			t.Assert(string(result), c.result)
+		}
+	})
+}
+
+func Test_GoFmtSource_Invalid(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		_, err := GoFmtSource([]byte("package a\n\nfunc {"))
+		t.AssertNE(err, nil)
+	})
+}